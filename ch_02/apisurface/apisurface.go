@@ -0,0 +1,237 @@
+// Package apisurface lists what a package exports and diffs two listings.
+//
+// unusedVarsAndNamingVars() points out that the case of the first letter decides whether a
+// package-level name is exported. this package makes that visible: every exported const, var,
+// func, type, field and method is written down with its signature, so two snapshots taken at
+// different revisions can be compared and breaking changes spotted.
+package apisurface
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"io"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Kind is the sort of declaration an Entry describes.
+type Kind string
+
+const (
+	Const  Kind = "const"
+	Var    Kind = "var"
+	Func   Kind = "func"
+	Type   Kind = "type"
+	Field  Kind = "field"
+	Method Kind = "method"
+)
+
+// Entry is a single exported identifier. Fields and methods are named Type.Name.
+type Entry struct {
+	Kind      Kind   `json:"kind"`
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+}
+
+func (e Entry) key() string { return string(e.Kind) + " " + e.Name }
+
+// Snapshot is the exported API of one package, sorted by name then kind so it diffs cleanly.
+type Snapshot struct {
+	Package string  `json:"package"`
+	Entries []Entry `json:"entries"`
+}
+
+// Load type-checks the packages matching patterns (anything `go list` accepts) and returns a
+// snapshot for each.
+func Load(dir string, patterns ...string) ([]Snapshot, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	var snaps []Snapshot
+	for _, p := range pkgs {
+		if len(p.Errors) > 0 {
+			return nil, fmt.Errorf("loading %s: %v", p.PkgPath, p.Errors[0])
+		}
+		snaps = append(snaps, FromPackage(p.Types))
+	}
+	slices.SortFunc(snaps, func(a, b Snapshot) int { return cmp.Compare(a.Package, b.Package) })
+	return snaps, nil
+}
+
+// FromPackage builds a snapshot from an already type-checked package.
+func FromPackage(pkg *types.Package) Snapshot {
+	// names from the package itself are written unqualified, everything else by import path.
+	qf := func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		return p.Path()
+	}
+
+	snap := Snapshot{Package: pkg.Path()}
+	add := func(k Kind, name, sig string) {
+		snap.Entries = append(snap.Entries, Entry{Kind: k, Name: name, Signature: sig})
+	}
+
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+		switch obj := obj.(type) {
+		case *types.Const:
+			add(Const, name, types.TypeString(obj.Type(), qf)+" = "+obj.Val().ExactString())
+		case *types.Var:
+			add(Var, name, types.TypeString(obj.Type(), qf))
+		case *types.Func:
+			add(Func, name, "func "+name+strings.TrimPrefix(types.TypeString(obj.Type(), qf), "func"))
+		case *types.TypeName:
+			addType(add, obj, qf)
+		}
+	}
+
+	slices.SortFunc(snap.Entries, func(a, b Entry) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Kind, b.Kind))
+	})
+	return snap
+}
+
+func addType(add func(Kind, string, string), obj *types.TypeName, qf types.Qualifier) {
+	name := obj.Name()
+	if obj.IsAlias() {
+		// aliases are materialised since Go 1.23, so the type prints as the alias's own name; the
+		// right-hand side is what callers depend on.
+		rhs := obj.Type()
+		if a, ok := rhs.(*types.Alias); ok {
+			rhs = a.Rhs()
+		}
+		add(Type, name, "= "+types.TypeString(rhs, qf))
+		return
+	}
+
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		add(Type, name, types.TypeString(obj.Type(), qf))
+		return
+	}
+
+	tparams := ""
+	if tp := named.TypeParams(); tp.Len() > 0 {
+		params := make([]string, tp.Len())
+		for i := range tp.Len() {
+			p := tp.At(i)
+			params[i] = p.Obj().Name() + " " + types.TypeString(p.Constraint(), qf)
+		}
+		tparams = "[" + strings.Join(params, ", ") + "]"
+	}
+
+	// a struct's unexported fields aren't part of its API, so they're left out of the type's
+	// signature and the exported ones get entries of their own.
+	if st, ok := named.Underlying().(*types.Struct); ok {
+		add(Type, name, tparams+"struct")
+		for i := range st.NumFields() {
+			f := st.Field(i)
+			if !f.Exported() {
+				continue
+			}
+			sig := types.TypeString(f.Type(), qf)
+			if f.Embedded() {
+				sig = "embedded " + sig
+			}
+			add(Field, name+"."+f.Name(), sig)
+		}
+	} else {
+		add(Type, name, tparams+types.TypeString(named.Underlying(), qf))
+	}
+
+	if _, ok := named.Underlying().(*types.Interface); ok {
+		// interface methods are already in the underlying type.
+		return
+	}
+
+	// methods on T are also in the method set of *T, so only the pointer-only ones need the star.
+	valueSet := types.NewMethodSet(named)
+	for _, set := range []*types.MethodSet{valueSet, types.NewMethodSet(types.NewPointer(named))} {
+		for i := range set.Len() {
+			sel := set.At(i)
+			m := sel.Obj()
+			if !m.Exported() {
+				continue
+			}
+			recv := name
+			if set != valueSet {
+				if valueSet.Lookup(m.Pkg(), m.Name()) != nil {
+					continue
+				}
+				recv = "*" + name
+			}
+			sig := strings.TrimPrefix(types.TypeString(sel.Type(), qf), "func")
+			add(Method, name+"."+m.Name(), "func ("+recv+") "+m.Name()+sig)
+		}
+	}
+}
+
+// WriteText writes snapshots in a line-based format that is easy to read in a code review:
+// a "package" line for each package followed by one tab-separated kind, name and signature per entry.
+func WriteText(w io.Writer, snaps []Snapshot) error {
+	bw := bufio.NewWriter(w)
+	for i, s := range snaps {
+		if i > 0 {
+			bw.WriteByte('\n')
+		}
+		fmt.Fprintf(bw, "package %s\n", s.Package)
+		for _, e := range s.Entries {
+			fmt.Fprintf(bw, "%s\t%s\t%s\n", e.Kind, e.Name, e.Signature)
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadText parses the output of WriteText.
+func ReadText(r io.Reader) ([]Snapshot, error) {
+	var snaps []Snapshot
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if line == "" {
+			continue
+		}
+		if pkg, ok := strings.CutPrefix(line, "package "); ok {
+			snaps = append(snaps, Snapshot{Package: pkg})
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 || len(snaps) == 0 {
+			return nil, fmt.Errorf("line %d: malformed entry %q", n, line)
+		}
+		last := &snaps[len(snaps)-1]
+		last.Entries = append(last.Entries, Entry{Kind: Kind(fields[0]), Name: fields[1], Signature: fields[2]})
+	}
+	return snaps, sc.Err()
+}
+
+// WriteJSON writes snapshots as indented JSON.
+func WriteJSON(w io.Writer, snaps []Snapshot) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(snaps)
+}
+
+// ReadJSON parses the output of WriteJSON.
+func ReadJSON(r io.Reader) ([]Snapshot, error) {
+	var snaps []Snapshot
+	err := json.NewDecoder(r).Decode(&snaps)
+	return snaps, err
+}
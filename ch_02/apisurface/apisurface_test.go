@@ -0,0 +1,210 @@
+package apisurface
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// check type-checks one file as the package example.com/p. nothing is imported, so no importer is
+// needed.
+func check(t *testing.T, src string) *types.Package {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := new(types.Config).Check("example.com/p", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkg
+}
+
+const v1 = `package p
+
+const MaxWins = 10
+const Greeting = "hi"
+const Rate float64 = 0.5
+
+var Default = Options{}
+
+type Options struct {
+	Name    string
+	private int
+}
+
+func (o Options) Valid() bool    { return true }
+func (o *Options) Reset()        {}
+func (o Options) hidden()        {}
+
+type Pair[K comparable, V any] struct{ Key K }
+
+type Stringer interface{ String() string }
+
+type ID = int
+
+func Open(name string) (*Options, error) { return nil, nil }
+func Close()                            {}
+func helper()                           {}
+`
+
+// v2 changes MaxWins' value, Rate's type, Open's signature, and drops Close. Name is still a
+// string field, and Options gains a field and a method.
+const v2 = `package p
+
+const MaxWins = 20
+const Greeting = "hi"
+const Rate float32 = 0.5
+
+var Default = Options{}
+
+type Options struct {
+	Name    string
+	Verbose bool
+}
+
+func (o Options) Valid() bool    { return true }
+func (o *Options) Reset()        {}
+func (o Options) String() string { return "" }
+
+type Pair[K comparable, V any] struct{ Key K }
+
+type Stringer interface{ String() string }
+
+type ID = int
+
+func Open(name string, flags int) (*Options, error) { return nil, nil }
+`
+
+func TestFromPackage(t *testing.T) {
+	got := FromPackage(check(t, v1))
+	want := []Entry{
+		{Func, "Close", "func Close()"},
+		{Const, "Greeting", "untyped string = \"hi\""},
+		{Type, "ID", "= int"},
+		{Const, "MaxWins", "untyped int = 10"},
+		{Func, "Open", "func Open(name string) (*Options, error)"},
+		{Type, "Options", "struct"},
+		{Field, "Options.Name", "string"},
+		{Method, "Options.Reset", "func (*Options) Reset()"},
+		{Method, "Options.Valid", "func (Options) Valid() bool"},
+		{Type, "Pair", "[K comparable, V any]struct"},
+		{Field, "Pair.Key", "K"},
+		{Const, "Rate", "float64 = 1/2"},
+		{Type, "Stringer", "interface{String() string}"},
+		{Var, "Default", "Options"},
+	}
+	if got.Package != "example.com/p" {
+		t.Errorf("Package = %q", got.Package)
+	}
+	byKey := map[string]Entry{}
+	for _, e := range got.Entries {
+		byKey[e.key()] = e
+	}
+	for _, e := range want {
+		if g, ok := byKey[e.key()]; !ok || g != e {
+			t.Errorf("entry %s = %+v, want %+v", e.key(), g, e)
+		}
+	}
+	if len(got.Entries) != len(want) {
+		t.Errorf("%d entries, want %d:\n%+v", len(got.Entries), len(want), got.Entries)
+	}
+	for i := 1; i < len(got.Entries); i++ {
+		if a, b := got.Entries[i-1], got.Entries[i]; a.Name > b.Name || (a.Name == b.Name && a.Kind > b.Kind) {
+			t.Errorf("entries out of order: %s before %s", a.key(), b.key())
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := []Snapshot{FromPackage(check(t, v1)), {Package: "example.com/gone", Entries: []Entry{{Func, "F", "func F()"}}}}
+	cur := []Snapshot{FromPackage(check(t, v2))}
+
+	var got []string
+	for _, c := range Diff(old, cur) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"! example.com/gone: - func F func F()",
+		"! example.com/p: - func Close func Close()",
+		"  example.com/p: ~ const MaxWins untyped int = 10 -> untyped int = 20",
+		"! example.com/p: ~ func Open func Open(name string) (*Options, error) -> func Open(name string, flags int) (*Options, error)",
+		"  example.com/p: + method Options.String func (Options) String() string",
+		"  example.com/p: + field Options.Verbose bool",
+		"! example.com/p: ~ const Rate float64 = 1/2 -> float32 = 1/2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if changes := Diff(old, old); len(changes) != 0 {
+		t.Errorf("Diff of a snapshot with itself = %v", changes)
+	}
+}
+
+func TestBreaking(t *testing.T) {
+	for _, c := range []struct {
+		change Change
+		want   bool
+	}{
+		{Change{Kind: Added, New: &Entry{Func, "F", "func F()"}}, false},
+		{Change{Kind: Removed, Old: &Entry{Func, "F", "func F()"}}, true},
+		{Change{Kind: Changed, Old: &Entry{Func, "F", "func F()"}, New: &Entry{Func, "F", "func F(int)"}}, true},
+		{Change{Kind: Changed, Old: &Entry{Const, "C", "int = 1"}, New: &Entry{Const, "C", "int = 2"}}, false},
+		{Change{Kind: Changed, Old: &Entry{Const, "C", "untyped int = 1"}, New: &Entry{Const, "C", "untyped float = 1.5"}}, true},
+		{Change{Kind: Changed, Old: &Entry{Const, "C", "int = 1"}, New: &Entry{Const, "C", "int64 = 1"}}, true},
+		{Change{Kind: Removed, Old: &Entry{Const, "C", "int = 1"}}, true},
+	} {
+		if got := c.change.Breaking(); got != c.want {
+			t.Errorf("%s: Breaking() = %v, want %v", c.change, got, c.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	snaps := []Snapshot{FromPackage(check(t, v1)), FromPackage(check(t, v2))}
+	snaps[1].Package = "example.com/p/v2"
+
+	var text bytes.Buffer
+	if err := WriteText(&text, snaps); err != nil {
+		t.Fatal(err)
+	}
+	fromText, err := ReadText(&text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromText, snaps) {
+		t.Errorf("text round trip:\n%+v\nwant\n%+v", fromText, snaps)
+	}
+
+	var js bytes.Buffer
+	if err := WriteJSON(&js, snaps); err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := ReadJSON(&js)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromJSON, snaps) {
+		t.Errorf("JSON round trip:\n%+v\nwant\n%+v", fromJSON, snaps)
+	}
+}
+
+func TestReadTextMalformed(t *testing.T) {
+	for _, in := range []string{
+		"func\tF\tfunc F()\n",                      // an entry before any package line
+		"package p\nfunc F\n",                      // too few fields
+		"package p\nfunc\tF\tfunc F()\nnonsense\n", // not an entry at all
+	} {
+		if snaps, err := ReadText(strings.NewReader(in)); err == nil {
+			t.Errorf("ReadText(%q) = %+v, want an error", in, snaps)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package apisurface
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ChangeKind says what happened to an entry between two snapshots.
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Change is one difference between two snapshots. Old is nil for additions and New is nil for
+// removals.
+type Change struct {
+	Package string
+	Kind    ChangeKind
+	Old     *Entry
+	New     *Entry
+}
+
+// Breaking reports whether code using the old API could stop compiling. anything that disappears
+// or changes its signature counts; new entries don't. a constant whose value changes but whose type
+// doesn't is a change, not a break: every use of it still compiles, which is how apidiff sees it
+// too.
+func (c Change) Breaking() bool {
+	switch c.Kind {
+	case Added:
+		return false
+	case Changed:
+		if c.Old.Kind == Const {
+			oldType, _, _ := strings.Cut(c.Old.Signature, " = ")
+			newType, _, _ := strings.Cut(c.New.Signature, " = ")
+			return oldType != newType
+		}
+	}
+	return true
+}
+
+func (c Change) String() string {
+	mark := " "
+	if c.Breaking() {
+		mark = "!"
+	}
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%s %s: + %s %s %s", mark, c.Package, c.New.Kind, c.New.Name, c.New.Signature)
+	case Removed:
+		return fmt.Sprintf("%s %s: - %s %s %s", mark, c.Package, c.Old.Kind, c.Old.Name, c.Old.Signature)
+	}
+	return fmt.Sprintf("%s %s: ~ %s %s %s -> %s", mark, c.Package, c.Old.Kind, c.Old.Name, c.Old.Signature, c.New.Signature)
+}
+
+// Diff compares two sets of snapshots package by package. a package that disappears shows up as
+// every one of its entries being removed.
+func Diff(old, cur []Snapshot) []Change {
+	byPkg := func(snaps []Snapshot) map[string]map[string]Entry {
+		m := map[string]map[string]Entry{}
+		for _, s := range snaps {
+			entries := map[string]Entry{}
+			for _, e := range s.Entries {
+				entries[e.key()] = e
+			}
+			m[s.Package] = entries
+		}
+		return m
+	}
+	oldPkgs, newPkgs := byPkg(old), byPkg(cur)
+
+	var changes []Change
+	for pkg, oldEntries := range oldPkgs {
+		newEntries := newPkgs[pkg]
+		for k, o := range oldEntries {
+			n, ok := newEntries[k]
+			switch {
+			case !ok:
+				changes = append(changes, Change{Package: pkg, Kind: Removed, Old: &o})
+			case n.Signature != o.Signature:
+				changes = append(changes, Change{Package: pkg, Kind: Changed, Old: &o, New: &n})
+			}
+		}
+	}
+	for pkg, newEntries := range newPkgs {
+		oldEntries := oldPkgs[pkg]
+		for k, n := range newEntries {
+			if _, ok := oldEntries[k]; !ok {
+				changes = append(changes, Change{Package: pkg, Kind: Added, New: &n})
+			}
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int {
+		return cmp.Or(cmp.Compare(a.Package, b.Package), cmp.Compare(a.entry().Name, b.entry().Name), cmp.Compare(a.entry().Kind, b.entry().Kind))
+	})
+	return changes
+}
+
+func (c Change) entry() *Entry {
+	if c.New != nil {
+		return c.New
+	}
+	return c.Old
+}
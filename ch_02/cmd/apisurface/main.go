@@ -0,0 +1,113 @@
+// Command apisurface snapshots the exported API of Go packages and diffs two snapshots.
+//
+//	go run ./cmd/apisurface -o api.txt ./...
+//	go run ./cmd/apisurface -json -o api.json ./...
+//	go run ./cmd/apisurface diff old.txt new.txt
+//
+// diff exits with status 1 when it finds a breaking change, so it can gate a CI job.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"ch_02/apisurface"
+)
+
+func main() {
+	var (
+		status int
+		err    error
+	)
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		status, err = diff(os.Args[2:])
+	} else {
+		err = snapshot()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "apisurface:", err)
+		os.Exit(2)
+	}
+	os.Exit(status)
+}
+
+func snapshot() error {
+	asJSON := flag.Bool("json", false, "write JSON instead of the text format")
+	out := flag.String("o", "", "write the snapshot to this file instead of stdout")
+	dir := flag.String("C", "", "load packages relative to this directory")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: apisurface [-json] [-o file] [-C dir] [packages]\n       apisurface diff old new")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	snaps, err := apisurface.Load(*dir, patterns...)
+	if err != nil {
+		return err
+	}
+
+	write := func(w io.Writer) error {
+		if *asJSON {
+			return apisurface.WriteJSON(w, snaps)
+		}
+		return apisurface.WriteText(w, snaps)
+	}
+	if *out == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	// a snapshot that didn't make it to disk shouldn't look like one that did.
+	return f.Close()
+}
+
+// diff prints the changes from old to new and returns the exit status: 1 if any of them break
+// callers, 2 for bad usage.
+func diff(args []string) (int, error) {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: apisurface diff old new")
+		return 2, nil
+	}
+	old, err := readSnapshots(args[0])
+	if err != nil {
+		return 0, err
+	}
+	cur, err := readSnapshots(args[1])
+	if err != nil {
+		return 0, err
+	}
+
+	status := 0
+	for _, c := range apisurface.Diff(old, cur) {
+		fmt.Println(c)
+		if c.Breaking() {
+			status = 1
+		}
+	}
+	return status, nil
+}
+
+// readSnapshots accepts either format, going by whether the file looks like JSON.
+func readSnapshots(path string) ([]apisurface.Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return apisurface.ReadJSON(bytes.NewReader(data))
+	}
+	return apisurface.ReadText(bytes.NewReader(data))
+}
@@ -0,0 +1,16 @@
+// Command declstyle checks Go code against the declaration rules from assignVars().
+//
+//	go run ./cmd/declstyle ./...
+//	go run ./cmd/declstyle -fix ./...
+//	go run ./cmd/declstyle -zerovalue.severity=off -packagevar.severity=warning ./...
+package main
+
+import (
+	"ch_02/declstyle"
+
+	"golang.org/x/tools/go/analysis/multichecker"
+)
+
+func main() {
+	multichecker.Main(declstyle.Analyzers...)
+}
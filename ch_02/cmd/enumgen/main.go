@@ -0,0 +1,261 @@
+// Command enumgen makes a named type with a const block behave like an enum.
+//
+// constantVars() shows const declaration lists, but a list of constants is just a list of numbers:
+// nothing prints their names or rejects a value that isn't one of them. given
+//
+//	type Color int
+//
+//	const (
+//		Red Color = iota
+//		Green
+//		Blue
+//		Crimson = Red // an alias, String() prints "Red"
+//	)
+//
+// and a `//go:generate go run ch_02/cmd/enumgen -type=Color` line, enumgen writes color_enum.go
+// with String, ParseColor, Values, IsValid, MarshalText and UnmarshalText. Values is a method, so
+// Color.Values() and Mode.Values() can live in the same package; call it on any value, Red.Values()
+// or Color(0).Values(), it ignores its receiver.
+//
+// For string types the constant's value is used as its name, so ModeFast Mode = "fast" prints
+// and parses as "fast".
+//
+// Values without a name (gaps in the sequence, or ones skipped with `_ = iota`) print as Color(3)
+// and aren't valid. When several names share a value the first one declared is used for String;
+// Parse accepts all of them.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/constant"
+	"go/format"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of type names; required")
+	output := flag.String("output", "", "output file name; default <type>_enum.go")
+	trimPrefix := flag.String("trimprefix", "", "trim this prefix from the names used by String and Parse")
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+
+	pkg, err := loadPackage(dir)
+	if err != nil {
+		fatal(err)
+	}
+
+	var enums []enum
+	for _, name := range strings.Split(*typeNames, ",") {
+		e, err := collect(pkg, strings.TrimSpace(name), *trimPrefix)
+		if err != nil {
+			fatal(err)
+		}
+		enums = append(enums, e)
+	}
+
+	src, err := generate(pkg.Name, strings.Join(os.Args[1:], " "), enums)
+	if err != nil {
+		fatal(err)
+	}
+
+	out := *output
+	if out == "" {
+		out = strings.ToLower(enums[0].Type) + "_enum.go"
+	}
+	if err := os.WriteFile(filepath.Join(dir, out), src, 0o644); err != nil {
+		fatal(err)
+	}
+}
+
+// enum is everything the template needs to know about one type.
+type enum struct {
+	Type       string
+	Underlying string
+	IsString   bool
+	Values     []value // one per distinct value, in declaration order
+	Names      []name  // every name, aliases included
+}
+
+type value struct {
+	Const string // the Go identifier
+	Name  string // what String returns
+}
+
+type name struct {
+	Name  string
+	Const string
+}
+
+func loadPackage(dir string) (*packages.Package, error) {
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax, Dir: dir}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+	if len(pkgs[0].Errors) > 0 {
+		return nil, pkgs[0].Errors[0]
+	}
+	return pkgs[0], nil
+}
+
+func collect(pkg *packages.Package, typeName, trimPrefix string) (enum, error) {
+	obj, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return enum{}, fmt.Errorf("no type %s in package %s", typeName, pkg.Name)
+	}
+	basic, ok := obj.Type().Underlying().(*types.Basic)
+	if !ok || basic.Info()&(types.IsInteger|types.IsString) == 0 {
+		return enum{}, fmt.Errorf("%s must have an integer or string underlying type", typeName)
+	}
+
+	// consts of the type, in the order they were written.
+	var consts []*types.Const
+	scope := pkg.Types.Scope()
+	for _, n := range scope.Names() {
+		c, ok := scope.Lookup(n).(*types.Const)
+		if ok && types.Identical(c.Type(), obj.Type()) {
+			consts = append(consts, c)
+		}
+	}
+	slices.SortFunc(consts, func(a, b *types.Const) int { return int(a.Pos() - b.Pos()) })
+	if len(consts) == 0 {
+		return enum{}, fmt.Errorf("no constants of type %s", typeName)
+	}
+
+	e := enum{
+		Type:       typeName,
+		Underlying: basic.Name(),
+		IsString:   basic.Info()&types.IsString != 0,
+	}
+	seen, seenName := map[string]bool{}, map[string]bool{}
+	for _, c := range consts {
+		n := strings.TrimPrefix(c.Name(), trimPrefix)
+		if e.IsString {
+			// a string enum already carries its text.
+			n = constant.StringVal(c.Val())
+		}
+		if !seenName[n] {
+			seenName[n] = true
+			e.Names = append(e.Names, name{Name: n, Const: c.Name()})
+		}
+
+		key := c.Val().ExactString()
+		if c.Val().Kind() == constant.Unknown || seen[key] {
+			continue
+		}
+		seen[key] = true
+		e.Values = append(e.Values, value{Const: c.Name(), Name: n})
+	}
+	return e, nil
+}
+
+func generate(pkgName, args string, enums []enum) ([]byte, error) {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, struct {
+		Package string
+		Args    string
+		Enums   []enum
+	}{pkgName, args, enums})
+	if err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "enumgen:", err)
+	os.Exit(1)
+}
+
+var tmpl = template.Must(template.New("enum").Parse(`// Code generated by "enumgen {{.Args}}"; DO NOT EDIT.
+
+package {{.Package}}
+
+import "fmt"
+{{range .Enums}}{{$t := .Type}}
+func (v {{$t}}) String() string {
+	switch v {
+{{- range .Values}}
+	case {{.Const}}:
+		return {{printf "%q" .Name}}
+{{- end}}
+	}
+{{- if .IsString}}
+	return fmt.Sprintf("{{$t}}(%q)", {{.Underlying}}(v))
+{{- else}}
+	return fmt.Sprintf("{{$t}}(%d)", {{.Underlying}}(v))
+{{- end}}
+}
+
+// Parse{{$t}} returns the {{$t}} called s. aliases are accepted as well.
+func Parse{{$t}}(s string) ({{$t}}, error) {
+	switch s {
+{{- range .Names}}
+	case {{printf "%q" .Name}}:
+		return {{.Const}}, nil
+{{- end}}
+	}
+	var zero {{$t}}
+	return zero, fmt.Errorf("invalid {{$t}} %q", s)
+}
+
+// Values returns every distinct {{$t}}, in declaration order. the receiver is ignored.
+func ({{$t}}) Values() []{{$t}} {
+	return []{{$t}}{
+{{- range .Values}}
+		{{.Const}},
+{{- end}}
+	}
+}
+
+// IsValid reports whether v is one of the declared constants.
+func (v {{$t}}) IsValid() bool {
+	switch v {
+	case {{range $i, $v := .Values}}{{if $i}}, {{end}}{{$v.Const}}{{end}}:
+		return true
+	}
+	return false
+}
+
+// MarshalText implements encoding.TextMarshaler. values without a name can't be marshaled.
+func (v {{$t}}) MarshalText() ([]byte, error) {
+	if !v.IsValid() {
+		return nil, fmt.Errorf("invalid {{$t}} %v", v)
+	}
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (v *{{$t}}) UnmarshalText(text []byte) error {
+	p, err := Parse{{$t}}(string(text))
+	if err != nil {
+		return err
+	}
+	*v = p
+	return nil
+}
+{{end}}`))
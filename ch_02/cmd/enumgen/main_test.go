@@ -0,0 +1,213 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// gaps (2, 4..9), a value skipped with _, and an alias.
+const colorSrc = `package colors
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	_
+	Blue
+	Purple  Color = 10
+	Crimson       = Red // an alias
+)
+
+type Mode string
+
+const (
+	ModeFast Mode = "fast"
+	ModeSafe Mode = "safe"
+	ModeQuick     = ModeFast
+)
+`
+
+// checks runs against the generated code; it prints what's wrong and exits 1.
+const checkSrc = `package colors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+func Check() {
+	var errs []string
+	fail := func(format string, args ...any) { errs = append(errs, fmt.Sprintf(format, args...)) }
+
+	if got, want := Red.Values(), []Color{Red, Green, Blue, Purple}; !slices.Equal(got, want) {
+		fail("Values() = %v, want %v", got, want)
+	}
+	for _, c := range []struct {
+		v    Color
+		want string
+	}{{Red, "Red"}, {Green, "Green"}, {Blue, "Blue"}, {Purple, "Purple"}, {Crimson, "Red"}, {2, "Color(2)"}, {5, "Color(5)"}, {-1, "Color(-1)"}} {
+		if got := c.v.String(); got != c.want {
+			fail("Color(%d).String() = %q, want %q", int(c.v), got, c.want)
+		}
+	}
+	for _, v := range []Color{2, 4, 9, 11} {
+		if v.IsValid() {
+			fail("Color(%d).IsValid() = true", int(v))
+		}
+		if _, err := v.MarshalText(); err == nil {
+			fail("Color(%d).MarshalText() succeeded", int(v))
+		}
+	}
+	for s, want := range map[string]Color{"Red": Red, "Crimson": Red, "Blue": Blue, "Purple": Purple} {
+		if got, err := ParseColor(s); err != nil || got != want {
+			fail("ParseColor(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "red", "_", "Color(2)"} {
+		if _, err := ParseColor(s); err == nil {
+			fail("ParseColor(%q) succeeded", s)
+		}
+	}
+
+	b, err := json.Marshal(map[string]Color{"a": Blue, "b": Crimson})
+	if err != nil || string(b) != ` + "`" + `{"a":"Blue","b":"Red"}` + "`" + ` {
+		fail("json.Marshal = %s, %v", b, err)
+	}
+	var back map[string]Color
+	if err := json.Unmarshal(b, &back); err != nil || back["a"] != Blue || back["b"] != Red {
+		fail("json.Unmarshal = %v, %v", back, err)
+	}
+
+	if got, want := ModeFast.Values(), []Mode{ModeFast, ModeSafe}; !slices.Equal(got, want) {
+		fail("Mode Values() = %v, want %v", got, want)
+	}
+	if got := ModeQuick.String(); got != "fast" {
+		fail("ModeQuick.String() = %q", got)
+	}
+	if got := Mode("slow").String(); got != ` + "`" + `Mode("slow")` + "`" + ` {
+		fail("Mode(slow).String() = %q", got)
+	}
+	if got, err := ParseMode("safe"); err != nil || got != ModeSafe {
+		fail("ParseMode(safe) = %v, %v", got, err)
+	}
+
+	for _, e := range errs {
+		fmt.Println(e)
+	}
+	if len(errs) > 0 {
+		os.Exit(1)
+	}
+}
+`
+
+// writeModule lays out a throwaway module holding colorSrc.
+func writeModule(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":    "module colors\n\ngo 1.25\n",
+		"colors.go": colorSrc,
+	})
+	return dir
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCollect(t *testing.T) {
+	pkg, err := loadPackage(writeModule(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := collect(pkg, "Color", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var consts, names []string
+	for _, v := range e.Values {
+		consts = append(consts, v.Const)
+	}
+	for _, n := range e.Names {
+		names = append(names, n.Name+"="+n.Const)
+	}
+	if want := []string{"Red", "Green", "Blue", "Purple"}; !slices.Equal(consts, want) {
+		t.Errorf("values = %v, want %v", consts, want)
+	}
+	if want := []string{"Red=Red", "Green=Green", "Blue=Blue", "Purple=Purple", "Crimson=Crimson"}; !slices.Equal(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+
+	if _, err := collect(pkg, "Nope", ""); err == nil {
+		t.Error("collect found a type that isn't there")
+	}
+}
+
+func TestTrimPrefix(t *testing.T) {
+	pkg, err := loadPackage(writeModule(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := collect(pkg, "Color", "Cr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := e.Names[len(e.Names)-1].Name; got != "imson" {
+		t.Errorf("trimmed alias name = %q, want %q", got, "imson")
+	}
+}
+
+// TestGenerated generates both enums and runs checkSrc against the result.
+func TestGenerated(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a module")
+	}
+	dir := writeModule(t)
+	pkg, err := loadPackage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var enums []enum
+	for _, typ := range []string{"Color", "Mode"} {
+		e, err := collect(pkg, typ, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		enums = append(enums, e)
+	}
+	src, err := generate(pkg.Name, "-type=Color,Mode", enums)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(src), `// Code generated by "enumgen -type=Color,Mode"; DO NOT EDIT.`) {
+		t.Errorf("generated file doesn't start with the generated-code header:\n%s", src)
+	}
+	writeFiles(t, dir, map[string]string{
+		"color_enum.go": string(src),
+		"check.go":      checkSrc,
+		"cmd/main.go":   "package main\n\nimport \"colors\"\n\nfunc main() { colors.Check() }\n",
+	})
+
+	cmd := exec.Command("go", "run", "./cmd")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("checks failed: %v\n%s\ngenerated:\n%s", err, out, src)
+	}
+}
@@ -0,0 +1,81 @@
+// Command floatbits shows how floats are stored.
+//
+//	go run ./cmd/floatbits 0.1 -0 1e-310 +Inf NaN
+//	go run ./cmd/floatbits -32 0.1
+//	go run ./cmd/floatbits -bits 0x7ff4000000000000
+//	go run ./cmd/floatbits -explain 0.1 0.2 0.3
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"ch_02/floatbits"
+)
+
+func main() {
+	use32 := flag.Bool("32", false, "treat values as float32")
+	rawBits := flag.Bool("bits", false, "arguments are raw bit patterns (e.g. 0x7ff4000000000000) instead of numbers")
+	explain := flag.Bool("explain", false, "take three arguments a, b and want and explain whether a+b == want")
+	flag.Parse()
+
+	if *explain {
+		if flag.NArg() != 3 {
+			fatal(fmt.Errorf("-explain needs three values, e.g. 0.1 0.2 0.3"))
+		}
+		var v [3]float64
+		for i, arg := range flag.Args() {
+			f, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				fatal(err)
+			}
+			v[i] = f
+		}
+		fmt.Print(floatbits.ExplainSum(v[0], v[1], v[2]))
+		return
+	}
+
+	for i, arg := range flag.Args() {
+		a, err := anatomy(arg, *use32, *rawBits)
+		if err != nil {
+			fatal(err)
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(a)
+	}
+}
+
+func anatomy(arg string, use32, rawBits bool) (floatbits.Anatomy, error) {
+	size := 64
+	if use32 {
+		size = 32
+	}
+	if rawBits {
+		b, err := strconv.ParseUint(arg, 0, size)
+		if err != nil {
+			return floatbits.Anatomy{}, err
+		}
+		if use32 {
+			return floatbits.FromBits32(uint32(b)), nil
+		}
+		return floatbits.FromBits64(b), nil
+	}
+
+	f, err := strconv.ParseFloat(arg, size)
+	if err != nil {
+		return floatbits.Anatomy{}, err
+	}
+	if use32 {
+		return floatbits.Of32(float32(f)), nil
+	}
+	return floatbits.Of64(f), nil
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "floatbits:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,15 @@
+// Command naming runs the naming analyzer on its own.
+//
+//	go run ./cmd/naming ./...
+//	go run ./cmd/naming -localmax=6 -linesperchar=3 ./...
+package main
+
+import (
+	"ch_02/naming"
+
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(naming.Analyzer)
+}
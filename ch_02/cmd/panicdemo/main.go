@@ -0,0 +1,81 @@
+// Command panicdemo sets off every runtime panic the chapters warn about and prints what
+// panics.Run recovered from each.
+//
+//	go run ./cmd/panicdemo
+//	go run ./cmd/panicdemo nilmap divzero
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"ch_02/panics"
+)
+
+// each demo reproduces one of the warnings from the chapters. the operands are variables so the
+// compiler can't reject them up front.
+var demos = []struct {
+	name string
+	from string
+	run  func()
+}{
+	{"divzero", "types(): don't divide by 0", func() {
+		x, zero := 10, 0
+		fmt.Println(x / zero)
+	}},
+	{"nilmap", "mapsInGo(): writing to a nil map", func() {
+		var nilMap map[string]int
+		nilMap["wtf"] = 3
+	}},
+	{"index", "array(): reading past the end", func() {
+		a2 := []int{1, 2, 3, 4, 5}
+		i := len(a2)
+		fmt.Println(a2[i])
+	}},
+	{"slice", "slicesInGo(): slicing past the capacity", func() {
+		s5 := []string{"a", "b", "c", "d"}
+		end := cap(s5) + 1
+		fmt.Println(s5[1:end])
+	}},
+	{"convert", "slicesInGo(): converting a short slice into an array", func() {
+		s9a := []int{2, 4}
+		fmt.Println([3]int(s9a))
+	}},
+	{"nilptr", "reading a field through a nil pointer", func() {
+		type person struct{ name string }
+		var joe *person
+		fmt.Println(joe.name)
+	}},
+	{"assert", "a type assertion without comma-ok", func() {
+		var v any = "hello"
+		fmt.Println(v.(int))
+	}},
+	{"closechan", "closing a channel twice", func() {
+		ch := make(chan int)
+		close(ch)
+		close(ch)
+	}},
+	{"custom", "panic() called directly", func() {
+		panic("whatever tf this is")
+	}},
+}
+
+func main() {
+	want := map[string]bool{}
+	for _, arg := range os.Args[1:] {
+		want[arg] = true
+	}
+
+	for _, d := range demos {
+		if len(want) > 0 && !want[d.name] {
+			continue
+		}
+		fmt.Printf("== %s: %s\n", d.name, d.from)
+		err := panics.Run(d.run)
+		if pe, ok := panics.As(err); ok {
+			fmt.Println(pe.Report())
+		} else {
+			fmt.Println("no panic")
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Command quote prints Go literals in every spelling, and rewrites the string literals in Go files.
+//
+//	go run ./cmd/quote 'what the fuck is going on, "Carlos"?'
+//	go run ./cmd/quote -style hex héllo
+//	go run ./cmd/quote -rune a
+//	go run ./cmd/quote -lit '"a\tb"'
+//	go run ./cmd/quote -rewrite -style raw -w types.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"unicode/utf8"
+
+	"ch_02/quote"
+)
+
+func main() {
+	styleName := flag.String("style", "", "only print this style: minimal, ascii, hex, octal, unicode, longunicode or raw")
+	asRune := flag.Bool("rune", false, "treat each argument as a single rune")
+	lit := flag.Bool("lit", false, "arguments are Go literals to be unquoted first")
+	rewrite := flag.Bool("rewrite", false, "arguments are Go files whose string literals are rewritten in -style")
+	write := flag.Bool("w", false, "with -rewrite, write the result back to the file instead of stdout")
+	flag.Parse()
+
+	styles := []quote.Style{quote.Minimal, quote.ASCII, quote.Hex, quote.Octal, quote.Unicode, quote.LongUnicode, quote.Raw}
+	if *styleName != "" {
+		s, err := quote.ParseStyle(*styleName)
+		if err != nil {
+			fatal(err)
+		}
+		styles = []quote.Style{s}
+	}
+
+	if *rewrite {
+		if *styleName == "" {
+			fatal(fmt.Errorf("-rewrite needs a -style"))
+		}
+		for _, path := range flag.Args() {
+			rewriteFile(path, styles[0], *write)
+		}
+		return
+	}
+
+	for _, arg := range flag.Args() {
+		value := arg
+		if *lit {
+			v, err := strconv.Unquote(arg)
+			if err != nil {
+				fatal(fmt.Errorf("%s is not a Go literal: %w", arg, err))
+			}
+			value = v
+		}
+
+		for _, s := range styles {
+			if *asRune {
+				r, size := utf8.DecodeRuneInString(value)
+				if size != len(value) {
+					fatal(fmt.Errorf("%q is not a single rune", value))
+				}
+				out, err := quote.Rune(r, s)
+				if err != nil {
+					out = "(" + err.Error() + ")"
+				}
+				fmt.Printf("%-12s %s\n", s, out)
+				continue
+			}
+			fmt.Printf("%-12s %s\n", s, quote.String(value, s))
+		}
+	}
+}
+
+func rewriteFile(path string, style quote.Style, write bool) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		fatal(err)
+	}
+	out, n, err := quote.RewriteFile(path, src, style)
+	if err != nil {
+		fatal(err)
+	}
+	if !write {
+		os.Stdout.Write(out)
+		return
+	}
+	if n > 0 {
+		if err := os.WriteFile(path, out, 0o644); err != nil {
+			fatal(err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%s: %d literals rewritten\n", path, n)
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "quote:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,31 @@
+// Command zerovalue describes the zero value of a type expression.
+//
+//	go run ./cmd/zerovalue 'map[string]int'
+//	go run ./cmd/zerovalue '[]*person' 'struct{ name string; scores map[string]int }'
+//	go run ./cmd/zerovalue 'chan<- Employee' 'func(int, ...string) error'
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"ch_02/zerovalue"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: zerovalue type-expression...")
+		os.Exit(2)
+	}
+	for i, expr := range os.Args[1:] {
+		t, err := zerovalue.ParseType(expr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "zerovalue: %s: %v\n", expr, err)
+			os.Exit(1)
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(zerovalue.Describe(t))
+	}
+}
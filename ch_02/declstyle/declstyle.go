@@ -0,0 +1,332 @@
+// Package declstyle turns the `var` versus `:=` house rules from assignVars() into go/analysis analyzers.
+//
+// The rules are:
+//   - inside a function, favour `:=` over `var x = v` (shortvar)
+//   - only spell out a type with `var` when it isn't the type `:=` would pick anyway (redundanttype)
+//   - write `var dT1 byte = 40` instead of `dT2 := byte(40)` (conversion)
+//   - use `var` when a variable starts out as the zero value, `var x int` and not `x := 0` (zerovalue)
+//   - never declare variables in the package block, only constants (packagevar)
+//
+// packagevar is off unless switched on with -packagevar.severity: plenty of good Go keeps
+// sentinel errors, analyzers and lookup tables in package-level variables, this package included.
+//
+// Every analyzer takes a -severity flag (off, info, warning or error). The severity ends up in the
+// diagnostic's message and category, so a driver or CI script can decide what should fail a build.
+package declstyle
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+var (
+	ShortVarAnalyzer      = newAnalyzer("shortvar", "favour := over var with an initializer inside functions", Warning, runShortVar)
+	RedundantTypeAnalyzer = newAnalyzer("redundanttype", "only write a type with var when it isn't the type := would infer", Warning, runRedundantType)
+	ConversionAnalyzer    = newAnalyzer("conversion", "write `var x T = c` instead of `x := T(c)` for constants", Warning, runConversion)
+	ZeroValueAnalyzer     = newAnalyzer("zerovalue", "use `var x T` instead of `x := <zero value>`", Info, runZeroValue)
+	PackageVarAnalyzer    = newAnalyzer("packagevar", "never declare variables in the package block", Off, runPackageVar)
+)
+
+// Analyzers is every rule in this package, ready to hand to multichecker.Main.
+var Analyzers = []*analysis.Analyzer{
+	ShortVarAnalyzer,
+	RedundantTypeAnalyzer,
+	ConversionAnalyzer,
+	ZeroValueAnalyzer,
+	PackageVarAnalyzer,
+}
+
+// reporter is what each rule gets instead of pass.Report. it stamps the rule's name and severity on
+// the diagnostic and drops it if the rule is switched off.
+type reporter func(pos, end token.Pos, msg string, fixes ...analysis.SuggestedFix)
+
+func newAnalyzer(name, doc string, def Severity, run func(*analysis.Pass, *inspector.Inspector, reporter)) *analysis.Analyzer {
+	severity := def
+	a := &analysis.Analyzer{
+		Name:     name,
+		Doc:      doc,
+		URL:      "https://github.com/chiagxziem/learning-go/tree/main/ch_02/declstyle",
+		Requires: []*analysis.Analyzer{inspect.Analyzer},
+	}
+	a.Flags.Var(&severity, "severity", "how to report "+name+" findings: off, info, warning or error")
+
+	a.Run = func(pass *analysis.Pass) (any, error) {
+		if severity == Off {
+			return nil, nil
+		}
+		report := func(pos, end token.Pos, msg string, fixes ...analysis.SuggestedFix) {
+			if isGenerated(pass, pos) {
+				return
+			}
+			pass.Report(analysis.Diagnostic{
+				Pos:            pos,
+				End:            end,
+				Category:       name,
+				Message:        fmt.Sprintf("%s: %s", severity, msg),
+				SuggestedFixes: fixes,
+			})
+		}
+		run(pass, pass.ResultOf[inspect.Analyzer].(*inspector.Inspector), report)
+		return nil, nil
+	}
+	return a
+}
+
+func runShortVar(pass *analysis.Pass, in *inspector.Inspector, report reporter) {
+	in.Preorder([]ast.Node{(*ast.DeclStmt)(nil)}, func(n ast.Node) {
+		decl := n.(*ast.DeclStmt).Decl.(*ast.GenDecl)
+		if decl.Tok != token.VAR {
+			return
+		}
+		for _, spec := range decl.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if vs.Type != nil || len(vs.Values) == 0 {
+				continue
+			}
+			// `var x = 0` is zerovalue's business: it wants `var x int`, and rewriting to `x := 0`
+			// here would only have it rewritten back.
+			if len(vs.Values) == 1 && isUntypedZero(pass, vs.Values[0]) {
+				continue
+			}
+			msg := fmt.Sprintf("use %s := ... instead of var inside a function", identList(vs.Names))
+			if decl.Lparen.IsValid() {
+				// declaration lists can't be rewritten one spec at a time.
+				report(vs.Pos(), vs.End(), msg)
+				continue
+			}
+			report(decl.Pos(), decl.End(), msg, toShortVar(decl, vs))
+		}
+	})
+}
+
+func runRedundantType(pass *analysis.Pass, in *inspector.Inspector, report reporter) {
+	in.Preorder([]ast.Node{(*ast.DeclStmt)(nil)}, func(n ast.Node) {
+		decl := n.(*ast.DeclStmt).Decl.(*ast.GenDecl)
+		if decl.Tok != token.VAR {
+			return
+		}
+		for _, spec := range decl.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if vs.Type == nil || len(vs.Values) != len(vs.Names) {
+				continue
+			}
+			declared := pass.TypesInfo.TypeOf(vs.Type)
+			if declared == nil || !allInferAs(pass, vs.Values, declared) {
+				continue
+			}
+			msg := fmt.Sprintf("%s is what := would infer anyway, use %s := ...", types.ExprString(vs.Type), identList(vs.Names))
+			if decl.Lparen.IsValid() {
+				report(vs.Pos(), vs.End(), msg)
+				continue
+			}
+			report(decl.Pos(), decl.End(), msg, toShortVar(decl, vs))
+		}
+	})
+}
+
+func runConversion(pass *analysis.Pass, in *inspector.Inspector, report reporter) {
+	eachDefine(in, func(as *ast.AssignStmt) {
+		if len(as.Lhs) != 1 || len(as.Rhs) != 1 {
+			return
+		}
+		call, ok := ast.Unparen(as.Rhs[0]).(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 || call.Ellipsis.IsValid() {
+			return
+		}
+		tv, ok := pass.TypesInfo.Types[call.Fun]
+		if !ok || !tv.IsType() {
+			return
+		}
+		arg := call.Args[0]
+		natural := naturalType(pass, arg)
+		if natural == nil || !isUntyped(natural) || types.Identical(types.Default(natural), tv.Type) {
+			return
+		}
+		if _, ok := tv.Type.Underlying().(*types.Basic); !ok {
+			return
+		}
+		// a conversion can do more than an assignment: string('a') is "a", but 'a' can't be
+		// assigned to a string, so there's no var to write instead.
+		if !types.AssignableTo(natural, tv.Type) {
+			return
+		}
+
+		name := types.ExprString(as.Lhs[0])
+		typ := types.ExprString(call.Fun)
+		fix := analysis.SuggestedFix{
+			Message: fmt.Sprintf("Rewrite as var %s %s = ...", name, typ),
+			TextEdits: []analysis.TextEdit{
+				{Pos: as.Pos(), End: arg.Pos(), NewText: fmt.Appendf(nil, "var %s %s = ", name, typ)},
+				{Pos: arg.End(), End: as.End()},
+			},
+		}
+		report(as.Pos(), as.End(), fmt.Sprintf("use var %s %s = ... instead of converting a constant with :=", name, typ), fix)
+	})
+}
+
+func runZeroValue(pass *analysis.Pass, in *inspector.Inspector, report reporter) {
+	eachDefine(in, func(as *ast.AssignStmt) {
+		if len(as.Lhs) != 1 || len(as.Rhs) != 1 {
+			return
+		}
+		id, ok := as.Lhs[0].(*ast.Ident)
+		if !ok || id.Name == "_" || pass.TypesInfo.Defs[id] == nil {
+			return
+		}
+		if !isUntypedZero(pass, as.Rhs[0]) {
+			return
+		}
+
+		typ := types.Default(naturalType(pass, as.Rhs[0])).String()
+		fix := analysis.SuggestedFix{
+			Message:   fmt.Sprintf("Rewrite as var %s %s", id.Name, typ),
+			TextEdits: []analysis.TextEdit{{Pos: as.Pos(), End: as.End(), NewText: fmt.Appendf(nil, "var %s %s", id.Name, typ)}},
+		}
+		report(as.Pos(), as.End(), fmt.Sprintf("%s starts at the zero value, declare it with var %s %s", id.Name, id.Name, typ), fix)
+	})
+}
+
+func runPackageVar(pass *analysis.Pass, _ *inspector.Inspector, report reporter) {
+	for _, file := range pass.Files {
+		for _, d := range file.Decls {
+			decl, ok := d.(*ast.GenDecl)
+			if !ok || decl.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if isBlankOnly(vs.Names) {
+					// `var _ I = (*T)(nil)` is a compile-time assertion, not state.
+					continue
+				}
+				report(vs.Pos(), vs.End(), fmt.Sprintf("package-level variable %s, only declare constants in the package block", identList(vs.Names)))
+			}
+		}
+	}
+}
+
+// eachDefine calls fn for every := statement that stands on its own. the ones in the init of an
+// if, for or switch are left out: a var declaration isn't allowed there, so the rules that turn :=
+// into var have nothing to suggest.
+func eachDefine(in *inspector.Inspector, fn func(*ast.AssignStmt)) {
+	in.WithStack([]ast.Node{(*ast.AssignStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		as := n.(*ast.AssignStmt)
+		if !push || as.Tok != token.DEFINE {
+			return true
+		}
+		var init ast.Stmt
+		switch parent := stack[len(stack)-2].(type) {
+		case *ast.ForStmt:
+			init = parent.Init
+		case *ast.IfStmt:
+			init = parent.Init
+		case *ast.SwitchStmt:
+			init = parent.Init
+		case *ast.TypeSwitchStmt:
+			init = parent.Init
+		}
+		if init != as {
+			fn(as)
+		}
+		return true
+	})
+}
+
+// toShortVar rewrites a single (unparenthesized) var declaration into a := statement, keeping the
+// initializers exactly as they were written.
+func toShortVar(decl *ast.GenDecl, vs *ast.ValueSpec) analysis.SuggestedFix {
+	names := identList(vs.Names)
+	return analysis.SuggestedFix{
+		Message: fmt.Sprintf("Rewrite as %s := ...", names),
+		TextEdits: []analysis.TextEdit{
+			{Pos: decl.Pos(), End: vs.Values[0].Pos(), NewText: []byte(names + " := ")},
+		},
+	}
+}
+
+// naturalType type-checks expr on its own, without the declaration around it, which gives the
+// type it would have on the right of a :=. untyped constants stay untyped.
+func naturalType(pass *analysis.Pass, expr ast.Expr) types.Type {
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+	if err := types.CheckExpr(pass.Fset, pass.Pkg, expr.Pos(), expr, info); err != nil {
+		return nil
+	}
+	return info.Types[expr].Type
+}
+
+func allInferAs(pass *analysis.Pass, values []ast.Expr, declared types.Type) bool {
+	for _, v := range values {
+		t := naturalType(pass, v)
+		if t == nil {
+			return false
+		}
+		if isUntyped(t) {
+			t = types.Default(t)
+		}
+		if !types.Identical(t, declared) {
+			return false
+		}
+	}
+	return true
+}
+
+func isUntyped(t types.Type) bool {
+	b, ok := t.(*types.Basic)
+	return ok && b.Info()&types.IsUntyped != 0
+}
+
+// isUntypedZero reports whether expr is an untyped constant holding the zero value of its default
+// type: 0, 0.0, "", false.
+func isUntypedZero(pass *analysis.Pass, expr ast.Expr) bool {
+	natural := naturalType(pass, expr)
+	return natural != nil && isUntyped(natural) && isZero(pass.TypesInfo.Types[expr].Value)
+}
+
+func isZero(v constant.Value) bool {
+	if v == nil {
+		return false
+	}
+	switch v.Kind() {
+	case constant.Bool:
+		return !constant.BoolVal(v)
+	case constant.String:
+		return constant.StringVal(v) == ""
+	case constant.Int, constant.Float, constant.Complex:
+		return constant.Sign(v) == 0
+	}
+	return false
+}
+
+func isBlankOnly(names []*ast.Ident) bool {
+	for _, n := range names {
+		if n.Name != "_" {
+			return false
+		}
+	}
+	return true
+}
+
+func identList(names []*ast.Ident) string {
+	s := make([]string, len(names))
+	for i, n := range names {
+		s[i] = n.Name
+	}
+	return strings.Join(s, ", ")
+}
+
+func isGenerated(pass *analysis.Pass, pos token.Pos) bool {
+	for _, f := range pass.Files {
+		if f.FileStart <= pos && pos <= f.FileEnd {
+			return ast.IsGenerated(f)
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package declstyle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+func TestFixes(t *testing.T) {
+	for _, a := range []*analysis.Analyzer{ShortVarAnalyzer, RedundantTypeAnalyzer, ConversionAnalyzer, ZeroValueAnalyzer} {
+		t.Run(a.Name, func(t *testing.T) {
+			analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, a.Name)
+		})
+	}
+}
+
+// bothAnalyzer is shortvar and zerovalue as one analyzer, since analysistest runs one at a time.
+var bothAnalyzer = newAnalyzer("both", "shortvar and zerovalue together", Warning,
+	func(pass *analysis.Pass, in *inspector.Inspector, report reporter) {
+		runShortVar(pass, in, report)
+		runZeroValue(pass, in, report)
+	})
+
+func TestShortVarAndZeroValue(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), bothAnalyzer, "both")
+
+	// the fixed code is a package of its own, and neither rule has anything left to say about it.
+	golden, err := os.ReadFile(filepath.Join(analysistest.TestData(), "src", "both", "a.go.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "src", "both"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "src", "both", "a.go"), golden, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range analysistest.Run(&quietT{T: t}, dir, bothAnalyzer, "both") {
+		for _, d := range r.Diagnostics {
+			t.Errorf("%s: %s after fixing", r.Pass.Fset.Position(d.Pos), d.Message)
+		}
+	}
+}
+
+func TestPackageVar(t *testing.T) {
+	setSeverity(t, PackageVarAnalyzer, "error")
+	analysistest.Run(t, analysistest.TestData(), PackageVarAnalyzer, "packagevar")
+}
+
+func TestPackageVarOffByDefault(t *testing.T) {
+	// with no wants matched, any diagnostic would fail the run.
+	results := analysistest.Run(&quietT{T: t}, analysistest.TestData(), PackageVarAnalyzer, "packagevar")
+	for _, r := range results {
+		if len(r.Diagnostics) != 0 {
+			t.Errorf("packagevar reported %d diagnostics while off", len(r.Diagnostics))
+		}
+	}
+}
+
+func TestSeverityOff(t *testing.T) {
+	setSeverity(t, ShortVarAnalyzer, "off")
+	results := analysistest.Run(&quietT{T: t}, analysistest.TestData(), ShortVarAnalyzer, "shortvar")
+	for _, r := range results {
+		if len(r.Diagnostics) != 0 {
+			t.Errorf("shortvar reported %d diagnostics while off", len(r.Diagnostics))
+		}
+	}
+}
+
+func setSeverity(t *testing.T, a *analysis.Analyzer, s string) {
+	t.Helper()
+	old := a.Flags.Lookup("severity").Value.String()
+	if err := a.Flags.Set("severity", s); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { a.Flags.Set("severity", old) })
+}
+
+// quietT swallows analysistest's complaints about unmatched want comments, for runs that are
+// expected to report nothing at all.
+type quietT struct{ *testing.T }
+
+func (*quietT) Errorf(string, ...any) {}
@@ -0,0 +1,36 @@
+package declstyle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity controls how loudly a rule reports. "off" disables the rule entirely.
+type Severity int
+
+const (
+	Off Severity = iota
+	Info
+	Warning
+	Error
+)
+
+var severityNames = [...]string{"off", "info", "warning", "error"}
+
+func (s Severity) String() string {
+	if s < Off || s > Error {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// Set lets a Severity be used as a flag.Value, so every analyzer can take a -severity flag.
+func (s *Severity) Set(v string) error {
+	for i, name := range severityNames {
+		if strings.EqualFold(v, name) {
+			*s = Severity(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q (want off, info, warning or error)", v)
+}
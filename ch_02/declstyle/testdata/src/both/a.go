@@ -0,0 +1,16 @@
+package both
+
+import "fmt"
+
+// shortvar and zerovalue run together. applying every fix once has to leave code neither of them
+// complains about, or they'd take turns rewriting each other's fixes.
+func assignVars() {
+	var a = 0
+	b := 0    // want `b starts at the zero value`
+	var c = 5 // want `use c := \.\.\.`
+	d := 5
+	var e = "" // "" is zero: shortvar leaves it
+	f := ""    // want `f starts at the zero value`
+
+	fmt.Println(a, b, c, d, e, f)
+}
@@ -0,0 +1,18 @@
+package conversion
+
+import "fmt"
+
+func assignVars() {
+	var dT1 byte = 40
+	dT2 := byte(40) // want `warning: use var dT2 byte = \.\.\. instead of converting a constant with :=`
+	n := int(7)
+	f := float64(len("abc"))
+	b := byte('a') // want `warning: use var b byte = \.\.\.`
+	// string('a') is "a", but 'a' isn't a string constant: var s string = 'a' doesn't compile.
+	s := string('a')
+	if c := byte(1); c > 0 {
+		fmt.Println(c)
+	}
+
+	fmt.Println(dT1, dT2, n, f, b, s)
+}
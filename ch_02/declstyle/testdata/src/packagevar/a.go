@@ -0,0 +1,19 @@
+package packagevar
+
+import "fmt"
+
+// only declare constants in the package block.
+const greeting = "hello"
+
+var state = 1 // want `error: package-level variable state, only declare constants in the package block`
+
+var (
+	a, b int // want `error: package-level variable a, b`
+)
+
+// a compile-time assertion isn't state.
+var _ fmt.Stringer = (*thing)(nil)
+
+type thing struct{}
+
+func (*thing) String() string { return greeting }
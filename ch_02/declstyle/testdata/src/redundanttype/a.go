@@ -0,0 +1,14 @@
+package redundanttype
+
+import "fmt"
+
+func assignVars() {
+	var x int = 10       // want `warning: int is what := would infer anyway, use x := \.\.\.`
+	var s string = "hey" // want `warning: string is what := would infer anyway`
+
+	// byte isn't what := picks for 40, so this is the form to use.
+	var dT1 byte = 40
+	var f float32 = 1.5
+
+	fmt.Println(x, s, dT1, f)
+}
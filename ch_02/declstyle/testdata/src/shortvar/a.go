@@ -0,0 +1,21 @@
+package shortvar
+
+import "fmt"
+
+func assignVars() {
+	var y = 20.23          // want `warning: use y := \.\.\. instead of var inside a function`
+	var m1, m2 = 10, "hey" // want `warning: use m1, m2 := \.\.\.`
+
+	var x int = 10
+	var zV int
+	var (
+		dV1 int
+		dV2 = 20 // want `warning: use dV2 := \.\.\.`
+	)
+	eO1 := 100
+	// zero values are zerovalue's: it wants var z int, not z := 0.
+	var z = 0
+	var e = ""
+
+	fmt.Println(y, m1, m2, x, zV, dV1, dV2, eO1, z, e)
+}
@@ -0,0 +1,36 @@
+package zerovalue
+
+import "fmt"
+
+func assignVars() {
+	var zV int
+	count := 0    // want `info: count starts at the zero value, declare it with var count int`
+	name := ""    // want `info: name starts at the zero value, declare it with var name string`
+	done := false // want `info: done starts at the zero value`
+	eO1 := 100
+
+	fmt.Println(zV, count, name, done, eO1)
+}
+
+// a var isn't allowed in the init of an if, for or switch, so those are left alone; the same
+// declarations in the bodies aren't.
+func loops(v any) {
+	for i := 0; i < 3; i++ {
+		j := 0 // want `info: j starts at the zero value`
+		fmt.Println(i, j)
+	}
+	if n := 0; n == 0 {
+		m := 0 // want `info: m starts at the zero value`
+		fmt.Println(m)
+	}
+	switch s := ""; s {
+	case "":
+		t := "" // want `info: t starts at the zero value`
+		fmt.Println(t)
+	}
+	switch b := false; x := v.(type) {
+	case int:
+		c := false // want `info: c starts at the zero value`
+		fmt.Println(b, c, x)
+	}
+}
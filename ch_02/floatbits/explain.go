@@ -0,0 +1,70 @@
+package floatbits
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// ExplainSum walks through a + b == want, the classic being 0.1 + 0.2 != 0.3. it shows the value
+// actually stored for each operand, the exact mathematical sum of those stored values, what that
+// sum rounds to, and how far it lands from want in units in the last place.
+func ExplainSum(a, b, want float64) string {
+	var sb strings.Builder
+	got := a + b
+
+	fmt.Fprintf(&sb, "%v is stored as %s\n", a, Of64(a).Exact())
+	fmt.Fprintf(&sb, "%v is stored as %s\n", b, Of64(b).Exact())
+
+	sum := new(big.Rat).Add(new(big.Rat).SetFloat64(a), new(big.Rat).SetFloat64(b))
+	fmt.Fprintf(&sb, "their exact sum is %s\n", ratString(sum))
+	fmt.Fprintf(&sb, "which rounds to the nearest float64, %s (printed as %v)\n", Of64(got).Exact(), got)
+	fmt.Fprintf(&sb, "%v is stored as %s\n", want, Of64(want).Exact())
+
+	if got == want {
+		fmt.Fprintf(&sb, "so %v + %v == %v\n", a, b, want)
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "so %v + %v != %v: they are %d ulp apart (%g)\n", a, b, want, ulpDistance(got, want), got-want)
+	fmt.Fprintf(&sb, "compare with a tolerance instead, e.g. math.Abs(a+b-want) <= 1e-9*math.Max(math.Abs(a+b), math.Abs(want))\n")
+	return sb.String()
+}
+
+// ulpDistance counts the representable float64s between x and y. the count can be as large as
+// 2^64 - 2^53 (from -Inf to +Inf), which doesn't fit in an int64, so it saturates at
+// math.MaxInt64; only values of opposite sign near the ends of the range get there.
+func ulpDistance(x, y float64) int64 {
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return -1
+	}
+	// mapping the bits onto a number line makes neighbouring floats neighbouring integers, even
+	// across zero.
+	order := func(f float64) int64 {
+		b := int64(math.Float64bits(f))
+		if b < 0 {
+			return math.MinInt64 - b
+		}
+		return b
+	}
+	ox, oy := order(x), order(y)
+	if ox < oy {
+		ox, oy = oy, ox
+	}
+	// the difference always fits in a uint64, even where it doesn't in an int64.
+	d := uint64(ox) - uint64(oy)
+	if d > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(d)
+}
+
+// ratString prints a fraction whose denominator is a power of two in full. a denominator of 2^k
+// needs exactly k digits after the point.
+func ratString(r *big.Rat) string {
+	s := r.FloatString(r.Denom().BitLen() - 1)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
@@ -0,0 +1,206 @@
+// Package floatbits takes IEEE 754 floats apart.
+//
+// types() says floats are approximate, that dividing a non-zero float by zero gives +Inf or -Inf
+// and that 0/0 gives NaN. this package shows why: every float32 and float64 is a sign bit, a
+// biased exponent and a mantissa, and the value stored is almost never the decimal you typed.
+package floatbits
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Class is the kind of value a bit pattern encodes.
+type Class int
+
+const (
+	Zero Class = iota
+	Subnormal
+	Normal
+	Infinite
+	QuietNaN
+	SignalingNaN
+)
+
+func (c Class) String() string {
+	switch c {
+	case Zero:
+		return "zero"
+	case Subnormal:
+		return "subnormal"
+	case Normal:
+		return "normal"
+	case Infinite:
+		return "infinity"
+	case QuietNaN:
+		return "quiet NaN"
+	case SignalingNaN:
+		return "signaling NaN"
+	}
+	return fmt.Sprintf("Class(%d)", int(c))
+}
+
+// format describes the layout of one of the two binary formats.
+type format struct {
+	bits, expBits, mantBits int
+	bias                    int
+}
+
+var (
+	binary32 = format{bits: 32, expBits: 8, mantBits: 23, bias: 127}
+	binary64 = format{bits: 64, expBits: 11, mantBits: 52, bias: 1023}
+)
+
+// Anatomy is a float broken into its fields.
+type Anatomy struct {
+	Size     int    // 32 or 64
+	Raw      uint64 // the whole bit pattern
+	Sign     uint64 // 1 for negative
+	Exponent uint64 // the biased exponent field as stored
+	Mantissa uint64 // the fraction field, without the implicit leading 1
+	Class    Class
+
+	f format
+}
+
+// Of64 takes a float64 apart.
+func Of64(v float64) Anatomy { return decompose(math.Float64bits(v), binary64) }
+
+// Of32 takes a float32 apart.
+func Of32(v float32) Anatomy { return decompose(uint64(math.Float32bits(v)), binary32) }
+
+// FromBits64 and FromBits32 start from a raw bit pattern, which is the only way to get at
+// signaling NaNs: Go's arithmetic only ever produces quiet ones.
+func FromBits64(b uint64) Anatomy { return decompose(b, binary64) }
+func FromBits32(b uint32) Anatomy { return decompose(uint64(b), binary32) }
+
+func decompose(raw uint64, f format) Anatomy {
+	a := Anatomy{
+		Size:     f.bits,
+		Raw:      raw,
+		Sign:     raw >> (f.bits - 1),
+		Exponent: raw >> f.mantBits & (1<<f.expBits - 1),
+		Mantissa: raw & (1<<f.mantBits - 1),
+		f:        f,
+	}
+
+	maxExp := uint64(1<<f.expBits - 1)
+	quietBit := uint64(1) << (f.mantBits - 1)
+	switch {
+	case a.Exponent == 0 && a.Mantissa == 0:
+		a.Class = Zero
+	case a.Exponent == 0:
+		a.Class = Subnormal
+	case a.Exponent == maxExp && a.Mantissa == 0:
+		a.Class = Infinite
+	case a.Exponent == maxExp && a.Mantissa&quietBit != 0:
+		a.Class = QuietNaN
+	case a.Exponent == maxExp:
+		a.Class = SignalingNaN
+	default:
+		a.Class = Normal
+	}
+	return a
+}
+
+// Float64 is the value as a float64. float32 values convert exactly.
+func (a Anatomy) Float64() float64 {
+	if a.Size == 32 {
+		return float64(math.Float32frombits(uint32(a.Raw)))
+	}
+	return math.Float64frombits(a.Raw)
+}
+
+// UnbiasedExponent is the power of two the mantissa is scaled by. subnormals use the minimum
+// exponent, and the result means nothing for Inf and NaN.
+func (a Anatomy) UnbiasedExponent() int {
+	if a.Exponent == 0 {
+		return 1 - a.f.bias
+	}
+	return int(a.Exponent) - a.f.bias
+}
+
+// Significand is the mantissa with its implicit leading bit, which is 1 for normal numbers and 0
+// for subnormals and zero.
+func (a Anatomy) Significand() uint64 {
+	if a.Class == Normal {
+		return a.Mantissa | 1<<a.f.mantBits
+	}
+	return a.Mantissa
+}
+
+// Bits renders the pattern as sign, exponent and mantissa separated by spaces.
+func (a Anatomy) Bits() string {
+	s := fmt.Sprintf("%0*b", a.Size, a.Raw)
+	return s[:1] + " " + s[1:1+a.f.expBits] + " " + s[1+a.f.expBits:]
+}
+
+// Neighbours returns the next representable values below and above, in the same precision.
+func (a Anatomy) Neighbours() (below, above float64) {
+	v := a.Float64()
+	if a.Size == 32 {
+		f := float32(v)
+		return float64(math.Nextafter32(f, float32(math.Inf(-1)))), float64(math.Nextafter32(f, float32(math.Inf(1))))
+	}
+	return math.Nextafter(v, math.Inf(-1)), math.Nextafter(v, math.Inf(1))
+}
+
+// Exact is the decimal value the bits actually hold, with every digit. every finite float is a
+// fraction with a power of two below it, so the expansion always ends.
+func (a Anatomy) Exact() string {
+	switch a.Class {
+	case Infinite:
+		if a.Sign == 1 {
+			return "-Inf"
+		}
+		return "+Inf"
+	case QuietNaN, SignalingNaN:
+		return "NaN"
+	}
+
+	s := ratString(new(big.Rat).SetFloat64(a.Float64()))
+	if a.Sign == 1 && a.Class == Zero {
+		s = "-" + s
+	}
+	return s
+}
+
+// Formula spells out how the fields combine, e.g. "+1.1001100110011001100110011001100110011001100110011010b × 2^-4".
+func (a Anatomy) Formula() string {
+	sign := "+"
+	if a.Sign == 1 {
+		sign = "-"
+	}
+	switch a.Class {
+	case Infinite:
+		return sign + "Inf: exponent all ones, mantissa zero"
+	case QuietNaN:
+		return "NaN: exponent all ones, top mantissa bit set"
+	case SignalingNaN:
+		return "NaN: exponent all ones, top mantissa bit clear, payload non-zero"
+	}
+	lead := "1"
+	if a.Class != Normal {
+		lead = "0"
+	}
+	return fmt.Sprintf("%s%s.%0*bb × 2^%d", sign, lead, a.f.mantBits, a.Mantissa, a.UnbiasedExponent())
+}
+
+// String is a multi-line report of everything above.
+func (a Anatomy) String() string {
+	var b strings.Builder
+	below, above := a.Neighbours()
+	fmt.Fprintf(&b, "value:      %v (float%d)\n", a.Float64(), a.Size)
+	fmt.Fprintf(&b, "class:      %s\n", a.Class)
+	fmt.Fprintf(&b, "bits:       %s\n", a.Bits())
+	fmt.Fprintf(&b, "hex:        0x%0*x\n", a.Size/4, a.Raw)
+	fmt.Fprintf(&b, "sign:       %d\n", a.Sign)
+	fmt.Fprintf(&b, "exponent:   %d (biased), %d (unbiased)\n", a.Exponent, a.UnbiasedExponent())
+	fmt.Fprintf(&b, "mantissa:   %#x\n", a.Mantissa)
+	fmt.Fprintf(&b, "formula:    %s\n", a.Formula())
+	fmt.Fprintf(&b, "exact:      %s\n", a.Exact())
+	fmt.Fprintf(&b, "neighbours: %v < x < %v\n", below, above)
+	return b.String()
+}
@@ -0,0 +1,154 @@
+package floatbits
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestClass(t *testing.T) {
+	for _, c := range []struct {
+		a    Anatomy
+		want Class
+	}{
+		{Of64(0), Zero},
+		{Of64(math.Copysign(0, -1)), Zero},
+		{Of64(5e-324), Subnormal},
+		{Of64(-0x1p-1023), Subnormal},
+		{Of64(0x1p-1022), Normal}, // the smallest normal
+		{Of64(1), Normal},
+		{Of64(-math.MaxFloat64), Normal},
+		{Of64(math.Inf(1)), Infinite},
+		{Of64(math.Inf(-1)), Infinite},
+		{Of64(math.NaN()), QuietNaN},
+		{FromBits64(0x7FF8000000000000), QuietNaN},
+		{FromBits64(0xFFF8000000000001), QuietNaN},
+		{FromBits64(0x7FF0000000000001), SignalingNaN},
+		{FromBits64(0x7FF4000000000000), SignalingNaN},
+
+		{Of32(0), Zero},
+		{Of32(math.SmallestNonzeroFloat32), Subnormal},
+		{Of32(0x1p-126), Normal},
+		{Of32(float32(math.Inf(-1))), Infinite},
+		{FromBits32(0x7FC00000), QuietNaN},
+		{FromBits32(0x7F800001), SignalingNaN},
+	} {
+		if c.a.Class != c.want {
+			t.Errorf("%#x (float%d) is %s, want %s", c.a.Raw, c.a.Size, c.a.Class, c.want)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	a := Of64(-0.1)
+	if a.Sign != 1 || a.Exponent != 1019 || a.UnbiasedExponent() != -4 || a.Mantissa != 0x999999999999a {
+		t.Errorf("-0.1: sign %d, exponent %d (%d), mantissa %#x", a.Sign, a.Exponent, a.UnbiasedExponent(), a.Mantissa)
+	}
+	if a.Significand() != 0x1999999999999a {
+		t.Errorf("-0.1 significand = %#x", a.Significand())
+	}
+	if got := a.Bits(); got != "1 01111111011 1001100110011001100110011001100110011001100110011010" {
+		t.Errorf("Bits() = %s", got)
+	}
+	if got := a.Formula(); got != "-1.1001100110011001100110011001100110011001100110011010b × 2^-4" {
+		t.Errorf("Formula() = %s", got)
+	}
+	if sub := Of64(5e-324); sub.UnbiasedExponent() != -1022 || sub.Significand() != 1 {
+		t.Errorf("smallest subnormal: exponent %d, significand %d", sub.UnbiasedExponent(), sub.Significand())
+	}
+	if f := Of32(0.1); f.Float64() != float64(float32(0.1)) || f.Bits() != "0 01111011 10011001100110011001101" {
+		t.Errorf("float32 0.1 = %v, %s", f.Float64(), f.Bits())
+	}
+}
+
+func TestExact(t *testing.T) {
+	for _, c := range []struct {
+		a    Anatomy
+		want string
+	}{
+		{Of64(0.1), "0.1000000000000000055511151231257827021181583404541015625"},
+		{Of64(0.5), "0.5"},
+		{Of64(-2), "-2"},
+		{Of64(1e23), "99999999999999991611392"},
+		{Of64(math.Copysign(0, -1)), "-0"},
+		{Of32(0.1), "0.100000001490116119384765625"},
+		{Of64(math.Inf(-1)), "-Inf"},
+		{FromBits64(0x7FF0000000000001), "NaN"},
+	} {
+		if got := c.a.Exact(); got != c.want {
+			t.Errorf("Exact() of %v = %s, want %s", c.a.Float64(), got, c.want)
+		}
+	}
+
+	// 2^-1074 has 1074 digits after the point, the first 323 of them zeros.
+	got := Of64(5e-324).Exact()
+	if !strings.HasPrefix(got, "0."+strings.Repeat("0", 323)+"4940656458412465441765687928682213723650598026143247644255856825006755072702087518652998363616359923797965646954457177309266567103559397963987747960107818781263007131903114045278458171678489821036887186360569987307230500063874091535649843873124733972731696151400317153853980741262385655911710266585566867681870395603106249319452715914924553293054565444011274801297099995419319894090804165633245247571478690147267801593552386115501348035264934720193790268107107491703332226844753335720832431936092382893458368060106011506169809753078342277318329247904982524730776375927247874656084778203734469699533647017972677717585125660551199131504891101451037862738167250955837389733598993664809941164205702637090279242767544565229087538682506419718265533447265625") || len(got) != 2+1074 {
+		t.Errorf("Exact() of 5e-324 = %s", got)
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	for _, c := range []struct {
+		a            Anatomy
+		below, above float64
+	}{
+		{Of64(1), 1 - 0x1p-53, 1 + 0x1p-52},
+		{Of64(0), -5e-324, 5e-324},
+		{Of64(math.MaxFloat64), math.Nextafter(math.MaxFloat64, 0), math.Inf(1)},
+		{Of64(math.Inf(1)), math.MaxFloat64, math.Inf(1)},
+		{Of32(1), 1 - 0x1p-24, 1 + 0x1p-23},
+		{Of32(0), -math.SmallestNonzeroFloat32, math.SmallestNonzeroFloat32},
+	} {
+		below, above := c.a.Neighbours()
+		if below != c.below || above != c.above {
+			t.Errorf("Neighbours of %v (float%d) = %v, %v, want %v, %v", c.a.Float64(), c.a.Size, below, above, c.below, c.above)
+		}
+	}
+}
+
+func TestULPDistance(t *testing.T) {
+	a, b := 0.1, 0.2 // variables, or the constant sum would be exactly 0.3
+	for _, c := range []struct {
+		x, y float64
+		want int64
+	}{
+		{1, 1, 0},
+		{a + b, 0.3, 1},
+		{1, math.Nextafter(1, 2), 1},
+		{0, math.Copysign(0, -1), 0},
+		{5e-324, -5e-324, 2},
+		{math.MaxFloat64, math.Inf(1), 1},
+		{1, 2, 1 << 52},
+		// these two are further apart than an int64 can count.
+		{math.Inf(1), math.Inf(-1), math.MaxInt64},
+		{math.MaxFloat64, -math.MaxFloat64, math.MaxInt64},
+		{1, -1, 0x7FE0000000000000},
+		{math.NaN(), 1, -1},
+	} {
+		if got := ulpDistance(c.x, c.y); got != c.want {
+			t.Errorf("ulpDistance(%v, %v) = %d, want %d", c.x, c.y, got, c.want)
+		}
+		if got := ulpDistance(c.y, c.x); got != c.want {
+			t.Errorf("ulpDistance(%v, %v) = %d, want %d", c.y, c.x, got, c.want)
+		}
+	}
+}
+
+func TestExplainSum(t *testing.T) {
+	got := ExplainSum(0.1, 0.2, 0.3)
+	for _, line := range []string{
+		"0.1 is stored as 0.1000000000000000055511151231257827021181583404541015625\n",
+		"0.2 is stored as 0.200000000000000011102230246251565404236316680908203125\n",
+		"their exact sum is 0.3000000000000000166533453693773481063544750213623046875\n",
+		"which rounds to the nearest float64, 0.3000000000000000444089209850062616169452667236328125 (printed as 0.30000000000000004)\n",
+		"0.3 is stored as 0.299999999999999988897769753748434595763683319091796875\n",
+		"so 0.1 + 0.2 != 0.3: they are 1 ulp apart (5.551115123125783e-17)\n",
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("ExplainSum(0.1, 0.2, 0.3) is missing %q:\n%s", line, got)
+		}
+	}
+	if got := ExplainSum(0.5, 0.25, 0.75); !strings.Contains(got, "so 0.5 + 0.25 == 0.75\n") || strings.Contains(got, "tolerance") {
+		t.Errorf("ExplainSum(0.5, 0.25, 0.75) =\n%s", got)
+	}
+}
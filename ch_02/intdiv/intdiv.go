@@ -0,0 +1,198 @@
+// Package intdiv has the integer division types() calls "strange", in every rounding flavour.
+//
+// Go's / truncates towards zero and % takes the sign of the dividend, so -7/2 is -3 and -7%2 is -1.
+// that's one of several reasonable choices:
+//
+//	mode      -7/2  -7%2   7/-2  7%-2
+//	Truncate   -3    -1     -3    1
+//	Floor      -4     1     -4   -1
+//	Euclid     -4     1     -3    1
+//	Ceil       -3    -1     -3    1   (7/2 gives 4, -1)
+//	Round      -4     1     -4   -1   (halves go away from zero)
+//
+// in every mode q*b + r == a. the plain functions panic on a zero divisor just like /, and
+// MinInt/-1 wraps around just like it does in Go. the Checked functions return an error instead.
+package intdiv
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// Integer is every integer type, including named ones like time.Duration.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Mode picks how the quotient is rounded.
+type Mode int
+
+const (
+	Truncate Mode = iota // towards zero, what / does
+	Floor                // towards -Inf, the remainder takes the sign of the divisor
+	Euclid               // the remainder is never negative
+	Ceil                 // towards +Inf
+	Round                // to the nearest integer, halves away from zero
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Truncate:
+		return "truncate"
+	case Floor:
+		return "floor"
+	case Euclid:
+		return "euclid"
+	case Ceil:
+		return "ceil"
+	case Round:
+		return "round"
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+var (
+	ErrDivideByZero = errors.New("intdiv: division by zero")
+	// ErrOverflow means the result doesn't fit in the type: MinInt/-1, or (from CheckedMod and
+	// CheckedDivMod) a remainder that would have to be negative for an unsigned type, which is
+	// what rounding 7/2 up to 4 leaves.
+	ErrOverflow = errors.New("intdiv: result out of range")
+)
+
+// DivMod divides a by b, rounding the quotient as m says, and returns the quotient and remainder.
+// for unsigned types a mode that rounds up leaves a remainder that wraps around, like any unsigned
+// subtraction below zero: DivMod(uint8(7), 2, Ceil) is 4, 255.
+func DivMod[T Integer](a, b T, m Mode) (q, r T) {
+	if m < Truncate || m > Round {
+		panic(fmt.Sprintf("intdiv: unknown mode %d", int(m)))
+	}
+	q, r = a/b, a%b
+	if r == 0 {
+		return q, r
+	}
+
+	// the true quotient is negative when the signs differ. truncation rounded it towards zero, so
+	// each mode decides whether to move it one step away.
+	neg := (a < 0) != (b < 0)
+	switch m {
+	case Truncate:
+	case Floor:
+		if neg {
+			q, r = q-1, r+b
+		}
+	case Euclid:
+		if r < 0 {
+			if b > 0 {
+				q, r = q-1, r+b
+			} else {
+				q, r = q+1, r-b
+			}
+		}
+	case Ceil:
+		if !neg {
+			q, r = q+1, r-b
+		}
+	case Round:
+		// |r| >= |b| - |r| is 2|r| >= |b| without the overflow.
+		rm, bm := magnitude(r), magnitude(b)
+		if rm >= bm-rm {
+			if neg {
+				q, r = q-1, r+b
+			} else {
+				q, r = q+1, r-b
+			}
+		}
+	}
+	return q, r
+}
+
+// Div is the quotient from DivMod.
+func Div[T Integer](a, b T, m Mode) T {
+	q, _ := DivMod(a, b, m)
+	return q
+}
+
+// Mod is the remainder from DivMod.
+func Mod[T Integer](a, b T, m Mode) T {
+	_, r := DivMod(a, b, m)
+	return r
+}
+
+func DivFloor[T Integer](a, b T) T  { return Div(a, b, Floor) }
+func ModFloor[T Integer](a, b T) T  { return Mod(a, b, Floor) }
+func DivEuclid[T Integer](a, b T) T { return Div(a, b, Euclid) }
+func ModEuclid[T Integer](a, b T) T { return Mod(a, b, Euclid) }
+func DivCeil[T Integer](a, b T) T   { return Div(a, b, Ceil) }
+func ModCeil[T Integer](a, b T) T   { return Mod(a, b, Ceil) }
+func DivRound[T Integer](a, b T) T  { return Div(a, b, Round) }
+
+// CheckedDivMod is DivMod without the panics. it never divides when b is zero and refuses
+// results that would wrap around, the remainder included.
+func CheckedDivMod[T Integer](a, b T, m Mode) (q, r T, err error) {
+	q, r, rOK, err := checked(a, b, m)
+	if err == nil && !rOK {
+		err = ErrOverflow
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return q, r, nil
+}
+
+// CheckedDiv is the quotient from CheckedDivMod. only the quotient has to fit, so
+// CheckedDiv(uint8(7), 2, Ceil) is 4 even though CheckedMod refuses the same division.
+func CheckedDiv[T Integer](a, b T, m Mode) (T, error) {
+	q, _, _, err := checked(a, b, m)
+	return q, err
+}
+
+// CheckedMod is the remainder from CheckedDivMod.
+func CheckedMod[T Integer](a, b T, m Mode) (T, error) {
+	_, r, err := CheckedDivMod(a, b, m)
+	return r, err
+}
+
+// checked divides, returning an error for a zero divisor, an unknown mode or a quotient that
+// doesn't fit. rOK is false when the remainder doesn't fit either.
+func checked[T Integer](a, b T, m Mode) (q, r T, rOK bool, err error) {
+	if b == 0 {
+		return 0, 0, false, ErrDivideByZero
+	}
+	if m < Truncate || m > Round {
+		return 0, 0, false, fmt.Errorf("intdiv: unknown mode %d", int(m))
+	}
+	if isSigned[T]() && b == ^T(0) && a == minOf[T]() {
+		return 0, 0, false, ErrOverflow
+	}
+	q0 := a / b
+	q, r = DivMod(a, b, m)
+	// rounding moves the quotient at most one step from q0; it only overflowed if that step wrapped.
+	if (q == q0+1 && q < q0) || (q == q0-1 && q > q0) {
+		return 0, 0, false, ErrOverflow
+	}
+	// an unsigned quotient can only be rounded up, leaving a remainder below zero.
+	return q, r, isSigned[T]() || q == q0, nil
+}
+
+func isSigned[T Integer]() bool {
+	var zero T
+	return zero-1 < 0
+}
+
+func minOf[T Integer]() T {
+	if !isSigned[T]() {
+		return 0
+	}
+	var zero T
+	return T(1) << (unsafe.Sizeof(zero)*8 - 1)
+}
+
+// magnitude is |x| as a uint64, which also works for MinInt.
+func magnitude[T Integer](x T) uint64 {
+	if x < 0 {
+		return uint64(-(x + 1)) + 1
+	}
+	return uint64(x)
+}
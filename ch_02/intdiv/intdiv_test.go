@@ -0,0 +1,217 @@
+package intdiv
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"testing"
+)
+
+var modes = []Mode{Truncate, Floor, Euclid, Ceil, Round}
+
+// reference divides a by b in math/big, where nothing overflows, rounding as m says.
+func reference(a, b int64, m Mode) (q, r *big.Int) {
+	return referenceBig(big.NewInt(a), big.NewInt(b), m)
+}
+
+func referenceBig(a, b *big.Int, m Mode) (q, r *big.Int) {
+	q, r = new(big.Int).QuoRem(a, b, new(big.Int))
+	if r.Sign() == 0 {
+		return q, r
+	}
+	one := big.NewInt(1)
+	switch m {
+	case Floor:
+		if r.Sign() != b.Sign() {
+			q.Sub(q, one)
+		}
+	case Euclid:
+		q.Div(a, b)
+	case Ceil:
+		if r.Sign() == b.Sign() {
+			q.Add(q, one)
+		}
+	case Round:
+		twice := new(big.Int).Lsh(new(big.Int).Abs(r), 1)
+		if twice.Cmp(new(big.Int).Abs(b)) >= 0 {
+			q.Add(q, big.NewInt(int64(a.Sign()*b.Sign())))
+		}
+	}
+	r = new(big.Int).Sub(a, new(big.Int).Mul(q, b))
+	return q, r
+}
+
+func fits(x *big.Int, lo, hi int64) bool {
+	return x.IsInt64() && x.Int64() >= lo && x.Int64() <= hi
+}
+
+func TestInt8Exhaustive(t *testing.T) {
+	for _, m := range modes {
+		for a := math.MinInt8; a <= math.MaxInt8; a++ {
+			for b := math.MinInt8; b <= math.MaxInt8; b++ {
+				if b == 0 {
+					continue
+				}
+				wq, wr := reference(int64(a), int64(b), m)
+				q, r := DivMod(int8(a), int8(b), m)
+				// DivMod wraps exactly where Go's / does.
+				if q != int8(wq.Int64()) || r != int8(wr.Int64()) {
+					t.Fatalf("DivMod(%d, %d, %v) = %d, %d, want %d, %d", a, b, m, q, r, wq, wr)
+				}
+
+				cq, cr, err := CheckedDivMod(int8(a), int8(b), m)
+				ok := fits(wq, math.MinInt8, math.MaxInt8) && fits(wr, math.MinInt8, math.MaxInt8)
+				switch {
+				case ok && (err != nil || cq != q || cr != r):
+					t.Fatalf("CheckedDivMod(%d, %d, %v) = %d, %d, %v, want %d, %d", a, b, m, cq, cr, err, wq, wr)
+				case !ok && !errors.Is(err, ErrOverflow):
+					t.Fatalf("CheckedDivMod(%d, %d, %v) = %d, %d, %v, want ErrOverflow", a, b, m, cq, cr, err)
+				}
+			}
+		}
+	}
+}
+
+func TestUint8Exhaustive(t *testing.T) {
+	for _, m := range modes {
+		for a := 0; a <= math.MaxUint8; a++ {
+			for b := 1; b <= math.MaxUint8; b++ {
+				wq, wr := reference(int64(a), int64(b), m)
+				q, r := DivMod(uint8(a), uint8(b), m)
+				if q != uint8(wq.Int64()) || r != uint8(wr.Int64()) {
+					t.Fatalf("DivMod(%d, %d, %v) = %d, %d, want %d, %d", a, b, m, q, r, wq, wr)
+				}
+
+				// the quotient always fits; the remainder doesn't when it's rounded below zero.
+				cq, err := CheckedDiv(uint8(a), uint8(b), m)
+				if err != nil || int64(cq) != wq.Int64() {
+					t.Fatalf("CheckedDiv(%d, %d, %v) = %d, %v, want %d", a, b, m, cq, err, wq)
+				}
+				cr, err := CheckedMod(uint8(a), uint8(b), m)
+				if wr.Sign() < 0 {
+					if !errors.Is(err, ErrOverflow) {
+						t.Fatalf("CheckedMod(%d, %d, %v) = %d, %v, want ErrOverflow", a, b, m, cr, err)
+					}
+				} else if err != nil || int64(cr) != wr.Int64() {
+					t.Fatalf("CheckedMod(%d, %d, %v) = %d, %v, want %d", a, b, m, cr, err, wr)
+				}
+			}
+		}
+	}
+}
+
+func TestInt64Random(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	edges := []int64{math.MinInt64, math.MinInt64 + 1, -3, -2, -1, 1, 2, 3, math.MaxInt64 - 1, math.MaxInt64}
+	pick := func() int64 {
+		if rng.IntN(4) == 0 {
+			return edges[rng.IntN(len(edges))]
+		}
+		return rng.Int64() >> rng.IntN(63)
+	}
+	for range 100_000 {
+		a, b := pick(), pick()
+		if rng.IntN(2) == 0 {
+			a = -a
+		}
+		if b == 0 {
+			b = 1
+		}
+		for _, m := range modes {
+			wq, wr := reference(a, b, m)
+			q, r, err := CheckedDivMod(a, b, m)
+			if !wq.IsInt64() {
+				if !errors.Is(err, ErrOverflow) {
+					t.Fatalf("CheckedDivMod(%d, %d, %v) = %d, %d, %v, want ErrOverflow", a, b, m, q, r, err)
+				}
+				continue
+			}
+			if err != nil || q != wq.Int64() || r != wr.Int64() {
+				t.Fatalf("CheckedDivMod(%d, %d, %v) = %d, %d, %v, want %d, %d", a, b, m, q, r, err, wq, wr)
+			}
+		}
+	}
+}
+
+func TestUint64Random(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	for range 100_000 {
+		a, b := rng.Uint64()>>rng.IntN(64), max(1, rng.Uint64()>>rng.IntN(64))
+		for _, m := range modes {
+			wq, wr := referenceBig(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b), m)
+			q, err := CheckedDiv(a, b, m)
+			if err != nil || q != wq.Uint64() {
+				t.Fatalf("CheckedDiv(%d, %d, %v) = %d, %v, want %d", a, b, m, q, err, wq)
+			}
+			if _, r := DivMod(a, b, m); wr.Sign() >= 0 && r != wr.Uint64() {
+				t.Fatalf("DivMod(%d, %d, %v) remainder = %d, want %d", a, b, m, r, wr)
+			}
+		}
+	}
+}
+
+// the table in the package doc.
+func TestDocTable(t *testing.T) {
+	for _, c := range []struct {
+		m                  Mode
+		q1, r1, q2, r2, q3 int
+	}{
+		{Truncate, -3, -1, -3, 1, 3},
+		{Floor, -4, 1, -4, -1, 3},
+		{Euclid, -4, 1, -3, 1, 3},
+		{Ceil, -3, -1, -3, 1, 4},
+		{Round, -4, 1, -4, -1, 4},
+	} {
+		if q, r := DivMod(-7, 2, c.m); q != c.q1 || r != c.r1 {
+			t.Errorf("%v: -7/2 = %d, %d, want %d, %d", c.m, q, r, c.q1, c.r1)
+		}
+		if q, r := DivMod(7, -2, c.m); q != c.q2 || r != c.r2 {
+			t.Errorf("%v: 7/-2 = %d, %d, want %d, %d", c.m, q, r, c.q2, c.r2)
+		}
+		if q := Div(7, 2, c.m); q != c.q3 {
+			t.Errorf("%v: 7/2 = %d, want %d", c.m, q, c.q3)
+		}
+	}
+}
+
+func TestUnsignedRoundingUp(t *testing.T) {
+	if q, err := CheckedDiv(uint8(7), 2, Ceil); err != nil || q != 4 {
+		t.Errorf("CheckedDiv(7, 2, Ceil) = %d, %v, want 4", q, err)
+	}
+	if q, err := CheckedDiv(uint8(7), 2, Round); err != nil || q != 4 {
+		t.Errorf("CheckedDiv(7, 2, Round) = %d, %v, want 4", q, err)
+	}
+	if _, err := CheckedMod(uint8(7), 2, Ceil); !errors.Is(err, ErrOverflow) {
+		t.Errorf("CheckedMod(7, 2, Ceil) error = %v, want ErrOverflow", err)
+	}
+	if q, r := DivMod(uint8(7), 2, Ceil); q != 4 || r != 255 {
+		t.Errorf("DivMod(7, 2, Ceil) = %d, %d, want 4, 255", q, r)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := CheckedDiv(1, 0, Floor); !errors.Is(err, ErrDivideByZero) {
+		t.Errorf("CheckedDiv(1, 0) error = %v", err)
+	}
+	if _, err := CheckedDiv(int8(math.MinInt8), -1, Truncate); !errors.Is(err, ErrOverflow) {
+		t.Errorf("CheckedDiv(MinInt8, -1) error = %v", err)
+	}
+	if _, err := CheckedDiv(4, 2, Mode(9)); err == nil {
+		t.Error("CheckedDiv with an unknown mode succeeded")
+	}
+}
+
+func TestUnknownModePanics(t *testing.T) {
+	// an exact division doesn't need rounding, but the mode is still checked.
+	for _, a := range []int{4, 5} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("DivMod(%d, 2, Mode(9)) didn't panic", a)
+				}
+			}()
+			DivMod(a, 2, Mode(9))
+		}()
+	}
+}
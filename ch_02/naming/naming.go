@@ -0,0 +1,219 @@
+// Package naming checks identifiers against the naming advice in unusedVarsAndNamingVars():
+// no snake_case (index_counter), no SCREAMING_CASE constants, and "the smaller the scope, the
+// shorter the variable name should be".
+//
+// The length check works both ways. Package-level names shorter than -pkgmin are too terse for
+// something the whole package can see, and a local whose name is longer than its scope can justify
+// is flagged too. A local may be -localmax characters long, plus one more character for every
+// -linesperchar lines it stays in scope.
+package naming
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name: "naming",
+	Doc:  "flag snake_case, SCREAMING_CASE and names whose length doesn't fit their scope",
+	URL:  "https://github.com/chiagxziem/learning-go/tree/main/ch_02/naming",
+	Run:  run,
+}
+
+// thresholds, all settable as flags on the analyzer.
+var (
+	checkSnake   = true
+	checkCaps    = true
+	checkLength  = true
+	initialisms  = ""
+	pkgMin       = 3
+	localMax     = 8
+	linesPerChar = 4
+)
+
+func init() {
+	Analyzer.Flags.BoolVar(&checkSnake, "snake", checkSnake, "flag snake_case identifiers")
+	Analyzer.Flags.BoolVar(&checkCaps, "caps", checkCaps, "flag ALL_CAPS identifiers")
+	Analyzer.Flags.BoolVar(&checkLength, "length", checkLength, "score name length against scope size")
+	Analyzer.Flags.StringVar(&initialisms, "initialisms", initialisms, "comma-separated initialisms to accept as all-caps names, on top of the usual ones (ID, URL, HTTP, ...)")
+	Analyzer.Flags.IntVar(&pkgMin, "pkgmin", pkgMin, "shortest allowed package-level name")
+	Analyzer.Flags.IntVar(&localMax, "localmax", localMax, "longest allowed name for a local that is only in scope for one line")
+	Analyzer.Flags.IntVar(&linesPerChar, "linesperchar", linesPerChar, "lines of scope that buy a local one more character")
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	known := knownInitialisms()
+
+	// Defs is a map, so its order changes from run to run; the reports shouldn't.
+	ids := make([]*ast.Ident, 0, len(pass.TypesInfo.Defs))
+	for id := range pass.TypesInfo.Defs {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b *ast.Ident) int { return int(a.Pos() - b.Pos()) })
+
+	for _, id := range ids {
+		obj := pass.TypesInfo.Defs[id]
+		if obj == nil || id.Name == "_" || isGenerated(pass, id.Pos()) {
+			continue
+		}
+		if _, ok := obj.(*types.PkgName); ok {
+			// import names are chosen by the imported package.
+			continue
+		}
+		if fn, ok := obj.(*types.Func); ok && isTestFunc(fn) {
+			continue
+		}
+
+		name := id.Name
+		switch {
+		case checkCaps && isScreaming(name):
+			pass.Reportf(id.Pos(), "%s is SCREAMING_CASE, Go uses MixedCaps (%s) and the first letter decides if it's exported", name, toMixedCaps(name))
+		case checkCaps && isAllCaps(name) && !isInitialism(name, known):
+			pass.Reportf(id.Pos(), "%s is all caps, use MixedCaps", name)
+		case checkSnake && isSnake(name):
+			pass.Reportf(id.Pos(), "%s is snake_case, use %s", name, toMixedCaps(name))
+		}
+
+		if checkLength {
+			checkNameLength(pass, id, obj)
+		}
+	}
+	return nil, nil
+}
+
+func checkNameLength(pass *analysis.Pass, id *ast.Ident, obj types.Object) {
+	if obj.Parent() == pass.Pkg.Scope() {
+		if len(id.Name) < pkgMin && id.Name != "main" {
+			pass.Reportf(id.Pos(), "%s is declared in the package block, use a more descriptive name (at least %d characters)", id.Name, pkgMin)
+		}
+		return
+	}
+
+	v, ok := obj.(*types.Var)
+	if !ok || v.IsField() || v.Parent() == nil {
+		return
+	}
+	lines := scopeLines(pass, v)
+	if allowed := localMax + lines/linesPerChar; len(id.Name) > allowed {
+		pass.Reportf(id.Pos(), "%s is %d characters long but only in scope for %d lines, try a name of at most %d characters", id.Name, len(id.Name), lines, allowed)
+	}
+}
+
+// scopeLines is the number of lines between a local's declaration and the end of the block it lives in.
+func scopeLines(pass *analysis.Pass, v *types.Var) int {
+	end := v.Parent().End()
+	if !end.IsValid() {
+		return 1
+	}
+	return pass.Fset.Position(end).Line - pass.Fset.Position(v.Pos()).Line + 1
+}
+
+func isSnake(name string) bool {
+	trimmed := strings.Trim(name, "_")
+	return strings.Contains(trimmed, "_")
+}
+
+func isScreaming(name string) bool {
+	return isAllCaps(name) && strings.Contains(strings.Trim(name, "_"), "_")
+}
+
+// commonInitialisms are the all-caps words Go names keep as they are (ServeHTTP, userID), mostly
+// the list golint used.
+var commonInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CRC", "CSS", "CSV", "DB", "DNS", "EOF", "FS", "GC", "GUID",
+	"HTML", "HTTP", "HTTPS", "ID", "IO", "IP", "JSON", "LHS", "OK", "OS", "QPS", "RAM", "RHS",
+	"RPC", "SHA", "SLA", "SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "URI",
+	"URL", "UTC", "UTF", "UUID", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
+func knownInitialisms() map[string]bool {
+	known := map[string]bool{}
+	for _, s := range commonInitialisms {
+		known[s] = true
+	}
+	for s := range strings.SplitSeq(initialisms, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			known[strings.ToUpper(s)] = true
+		}
+	}
+	return known
+}
+
+// isInitialism reports whether an all-caps name is nothing but known initialisms run together,
+// digits allowed between them: ASCII, UTF8 and HTTPAPI are, STATUS isn't.
+func isInitialism(name string, known map[string]bool) bool {
+	// ok[i] is whether name[:i] splits into initialisms and digits.
+	ok := make([]bool, len(name)+1)
+	ok[0] = true
+	for i := range len(name) {
+		if !ok[i] {
+			continue
+		}
+		if unicode.IsDigit(rune(name[i])) {
+			ok[i+1] = true
+			continue
+		}
+		for j := i + 1; j <= len(name); j++ {
+			if known[name[i:j]] {
+				ok[j] = true
+			}
+		}
+	}
+	return ok[len(name)]
+}
+
+func isAllCaps(name string) bool {
+	letters := 0
+	for _, r := range name {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters > 1
+}
+
+// toMixedCaps suggests a replacement, keeping the case of the first letter so the suggestion
+// doesn't change whether the name is exported.
+func toMixedCaps(name string) string {
+	var b strings.Builder
+	for i, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' }) {
+		if isAllCaps(part) || len(part) == 1 {
+			part = strings.ToLower(part)
+		}
+		if i > 0 || unicode.IsUpper(rune(name[0])) {
+			part = strings.ToUpper(part[:1]) + part[1:]
+		}
+		b.WriteString(part)
+	}
+	if b.Len() == 0 {
+		return name
+	}
+	return b.String()
+}
+
+func isTestFunc(fn *types.Func) bool {
+	for _, prefix := range []string{"Test", "Benchmark", "Example", "Fuzz"} {
+		if strings.HasPrefix(fn.Name(), prefix+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+func isGenerated(pass *analysis.Pass, pos token.Pos) bool {
+	for _, f := range pass.Files {
+		if f.FileStart <= pos && pos <= f.FileEnd {
+			return ast.IsGenerated(f)
+		}
+	}
+	return false
+}
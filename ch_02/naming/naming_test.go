@@ -0,0 +1,52 @@
+package naming
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestNaming(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "names")
+}
+
+func TestExtraInitialisms(t *testing.T) {
+	Analyzer.Flags.Set("initialisms", "zip")
+	defer Analyzer.Flags.Set("initialisms", "")
+	results := analysistest.Run(&quietT{T: t}, analysistest.TestData(), Analyzer, "names")
+	for _, r := range results {
+		for _, d := range r.Diagnostics {
+			if d.Message == "ZIP is all caps, use MixedCaps" {
+				t.Errorf("ZIP reported with -initialisms=zip")
+			}
+		}
+	}
+}
+
+func TestReportOrder(t *testing.T) {
+	results := analysistest.Run(t, analysistest.TestData(), Analyzer, "names")
+	for _, r := range results {
+		for i := 1; i < len(r.Diagnostics); i++ {
+			if r.Diagnostics[i].Pos < r.Diagnostics[i-1].Pos {
+				t.Errorf("diagnostic %d (%s) comes before diagnostic %d", i, r.Diagnostics[i].Message, i-1)
+			}
+		}
+	}
+}
+
+func TestIsInitialism(t *testing.T) {
+	known := knownInitialisms()
+	for name, want := range map[string]bool{
+		"ID": true, "ASCII": true, "UTF8": true, "HTTPAPI": true, "XMLHTTP": true,
+		"STATUS": false, "ZIP": false, "IDX": false,
+	} {
+		if got := isInitialism(name, known); got != want {
+			t.Errorf("isInitialism(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+// quietT swallows analysistest's complaints about want comments that aren't met.
+type quietT struct{ *testing.T }
+
+func (*quietT) Errorf(string, ...any) {}
@@ -0,0 +1,23 @@
+package names
+
+const MAX_SIZE = 10 // want `MAX_SIZE is SCREAMING_CASE, Go uses MixedCaps \(MaxSize\)`
+
+const STATUS = 1 // want `STATUS is all caps, use MixedCaps`
+
+// initialisms, alone or run together, are fine however long they are.
+const (
+	ASCII   = 1
+	HTTPS   = 2
+	UTF8    = 3
+	HTTPAPI = 4
+	ZIP     = 5 // want `ZIP is all caps, use MixedCaps`
+)
+
+var index_counter = 0 // want `index_counter is snake_case, use indexCounter`
+
+var ab = 0 // want `ab is declared in the package block, use a more descriptive name \(at least 3 characters\)`
+
+func use() int {
+	numberOfThings := 1 // want `numberOfThings is 14 characters long but only in scope for 3 lines, try a name of at most 8 characters`
+	return numberOfThings
+}
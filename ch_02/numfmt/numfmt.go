@@ -0,0 +1,166 @@
+// Package numfmt prints numbers for people instead of with a bare fmt.Println.
+//
+// literals() shows that Go lets you write 10_000 and 0x1a2b5, but fmt prints 10000 and 107189.
+// this package groups digits the way a locale (or Go source) does, prints other bases with their
+// prefixes, scales by SI and IEC units, rounds to significant figures, and parses all of it back.
+package numfmt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Locale says how digits are grouped and what the decimal point looks like.
+type Locale struct {
+	Group   string // between groups of digits
+	Decimal string // between the integer and fractional parts
+	// Sizes are group sizes counted from the decimal point. the last one repeats, so {3} is
+	// 1,000,000 and {3, 2} is the Indian 10,00,000.
+	Sizes []int
+}
+
+var (
+	English = Locale{Group: ",", Decimal: ".", Sizes: []int{3}}
+	German  = Locale{Group: ".", Decimal: ",", Sizes: []int{3}}
+	French  = Locale{Group: " ", Decimal: ",", Sizes: []int{3}} // narrow no-break space
+	Swiss   = Locale{Group: "’", Decimal: ".", Sizes: []int{3}}
+	Indian  = Locale{Group: ",", Decimal: ".", Sizes: []int{3, 2}}
+	// Go groups the way literals() does, 10_000. the output is a valid Go literal.
+	Go = Locale{Group: "_", Decimal: ".", Sizes: []int{3}}
+)
+
+// FormatInt writes n with its digits grouped.
+func FormatInt(n int64, loc Locale) string {
+	s := strconv.FormatInt(n, 10)
+	sign := ""
+	if s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	return sign + group(s, loc.Group, loc.Sizes)
+}
+
+// FormatUint is FormatInt for unsigned numbers.
+func FormatUint(n uint64, loc Locale) string {
+	return group(strconv.FormatUint(n, 10), loc.Group, loc.Sizes)
+}
+
+// FormatFloat writes f with prec digits after the decimal point (-1 for as many as it takes to
+// round-trip) and the integer part grouped. Inf and NaN come out as "+Inf", "-Inf" and "NaN".
+func FormatFloat(f float64, prec int, loc Locale) string {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return strconv.FormatFloat(f, 'f', prec, 64)
+	}
+	s := strconv.FormatFloat(f, 'f', prec, 64)
+	sign := ""
+	if s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac, hasFrac := strings.Cut(s, ".")
+	out := sign + group(intPart, loc.Group, loc.Sizes)
+	if hasFrac {
+		out += loc.Decimal + frac
+	}
+	return out
+}
+
+// group inserts sep into a string of digits, from the right.
+func group(digits, sep string, sizes []int) string {
+	if sep == "" || len(sizes) == 0 {
+		return digits
+	}
+	var parts []string
+	for i := 0; len(digits) > 0; i++ {
+		size := sizes[min(i, len(sizes)-1)]
+		if size <= 0 || size >= len(digits) {
+			parts = append(parts, digits)
+			break
+		}
+		parts = append(parts, digits[len(digits)-size:])
+		digits = digits[:len(digits)-size]
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, sep)
+}
+
+// ParseInt reads a number written by FormatInt. the group separator is only allowed between
+// digits, but group sizes aren't checked, so "1,0000" still reads as 10000.
+func ParseInt(s string, loc Locale) (int64, error) {
+	digits, err := ungroup(s, loc.Group)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(digits, 10, 64)
+}
+
+// ParseUint reads a number written by FormatUint.
+func ParseUint(s string, loc Locale) (uint64, error) {
+	digits, err := ungroup(s, loc.Group)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(digits, 10, 64)
+}
+
+// ParseFloat reads a number written by FormatFloat.
+func ParseFloat(s string, loc Locale) (float64, error) {
+	intPart, frac, hasFrac := strings.Cut(s, loc.Decimal)
+	if loc.Decimal == "" {
+		intPart, hasFrac = s, false
+	}
+	digits, err := ungroup(intPart, loc.Group)
+	if err != nil {
+		return 0, err
+	}
+	if hasFrac {
+		digits += "." + frac
+	}
+	return strconv.ParseFloat(digits, 64)
+}
+
+func ungroup(s, sep string) (string, error) {
+	if sep == "" || !strings.Contains(s, sep) {
+		return s, nil
+	}
+	parts := strings.Split(s, sep)
+	for i, p := range parts {
+		if i == 0 {
+			p = strings.TrimPrefix(p, "-")
+		}
+		if p == "" {
+			return "", fmt.Errorf("numfmt: misplaced group separator in %q", s)
+		}
+	}
+	return strings.Join(parts, ""), nil
+}
+
+// FormatBase writes n in base 2, 8, 10 or 16 with Go's prefix (0b, 0o, 0x) and, when groupSize is
+// positive, an underscore between every groupSize digits: FormatBase(0x1a2b5, 16, 4) is
+// "0x1_a2b5". the result is always a valid Go literal and ParseBase reads it back.
+func FormatBase(n int64, base, groupSize int) (string, error) {
+	prefix, ok := prefixes[base]
+	if !ok {
+		return "", fmt.Errorf("numfmt: unsupported base %d", base)
+	}
+	sign := ""
+	mag := uint64(n)
+	if n < 0 {
+		sign, mag = "-", uint64(-(n+1))+1
+	}
+	digits := strconv.FormatUint(mag, base)
+	if groupSize > 0 {
+		digits = group(digits, "_", []int{groupSize})
+	}
+	return sign + prefix + digits, nil
+}
+
+var prefixes = map[int]string{2: "0b", 8: "0o", 10: "", 16: "0x"}
+
+// ParseBase reads any Go integer literal: a prefix picks the base and underscores are allowed
+// between digits.
+func ParseBase(s string) (int64, error) {
+	return strconv.ParseInt(s, 0, 64)
+}
@@ -0,0 +1,135 @@
+package numfmt
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFormatInt(t *testing.T) {
+	for _, c := range []struct {
+		n    int64
+		loc  Locale
+		want string
+	}{
+		{0, English, "0"},
+		{999, English, "999"},
+		{1000, English, "1,000"},
+		{-1234567, English, "-1,234,567"},
+		{1234567, German, "1.234.567"},
+		{1234567, French, "1\u202f234\u202f567"},
+		{1234567, Swiss, "1’234’567"},
+		{1234567, Indian, "12,34,567"},
+		{100000, Indian, "1,00,000"},
+		{10000, Go, "10_000"},
+		{math.MinInt64, English, "-9,223,372,036,854,775,808"},
+		{1234567, Locale{Decimal: "."}, "1234567"},
+	} {
+		if got := FormatInt(c.n, c.loc); got != c.want {
+			t.Errorf("FormatInt(%d, %+v) = %q, want %q", c.n, c.loc, got, c.want)
+		}
+		if got, err := ParseInt(c.want, c.loc); err != nil || got != c.n {
+			t.Errorf("ParseInt(%q) = %d, %v, want %d", c.want, got, err, c.n)
+		}
+	}
+	if got := FormatUint(math.MaxUint64, Go); got != "18_446_744_073_709_551_615" {
+		t.Errorf("FormatUint(MaxUint64) = %q", got)
+	}
+	if got, err := ParseUint("18_446_744_073_709_551_615", Go); err != nil || got != math.MaxUint64 {
+		t.Errorf("ParseUint = %d, %v", got, err)
+	}
+}
+
+func TestParseIntBad(t *testing.T) {
+	for _, s := range []string{",100", "100,", "1,,000", "-,100", "1.000", "", "1,000x"} {
+		if n, err := ParseInt(s, English); err == nil {
+			t.Errorf("ParseInt(%q) = %d, want an error", s, n)
+		}
+	}
+	// group sizes aren't checked.
+	if n, err := ParseInt("1,0000", English); err != nil || n != 10000 {
+		t.Errorf("ParseInt(\"1,0000\") = %d, %v", n, err)
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	for _, c := range []struct {
+		f    float64
+		prec int
+		loc  Locale
+		want string
+	}{
+		{1234567.891, 2, English, "1,234,567.89"},
+		{1234567.891, 2, German, "1.234.567,89"},
+		{1234567.891, 1, French, "1\u202f234\u202f567,9"},
+		{-1234.5, -1, Indian, "-1,234.5"},
+		{123456.75, 2, Indian, "1,23,456.75"},
+		{0.5, 0, English, "0"},
+		{math.Inf(1), 2, English, "+Inf"},
+	} {
+		got := FormatFloat(c.f, c.prec, c.loc)
+		if got != c.want {
+			t.Errorf("FormatFloat(%v, %d, %+v) = %q, want %q", c.f, c.prec, c.loc, got, c.want)
+		}
+	}
+	for _, c := range []struct {
+		s    string
+		loc  Locale
+		want float64
+	}{
+		{"1,234,567.89", English, 1234567.89},
+		{"1.234.567,89", German, 1234567.89},
+		{"1\u202f234\u202f567,9", French, 1234567.9},
+		{"-12,34,567.5", Indian, -1234567.5},
+		{"1’000.25", Swiss, 1000.25},
+		{"10_000.5", Go, 10000.5},
+		{"42", German, 42},
+	} {
+		if got, err := ParseFloat(c.s, c.loc); err != nil || got != c.want {
+			t.Errorf("ParseFloat(%q) = %v, %v, want %v", c.s, got, err, c.want)
+		}
+	}
+	for _, s := range []string{"1,234.5", ".5,5", "1..0"} {
+		if f, err := ParseFloat(s, German); err == nil {
+			t.Errorf("ParseFloat(%q, German) = %v, want an error", s, f)
+		}
+	}
+}
+
+func TestFormatBase(t *testing.T) {
+	for _, c := range []struct {
+		n               int64
+		base, groupSize int
+		want            string
+	}{
+		{0x1a2b5, 16, 4, "0x1_a2b5"},
+		{0x1a2b5, 16, 0, "0x1a2b5"},
+		{5, 2, 0, "0b101"},
+		{255, 2, 4, "0b1111_1111"},
+		{8, 8, 0, "0o10"},
+		{1000000, 10, 3, "1_000_000"},
+		{0, 16, 4, "0x0"},
+		{-255, 16, 0, "-0xff"},
+		{-5, 2, 0, "-0b101"},
+		{math.MinInt64, 16, 4, "-0x8000_0000_0000_0000"},
+		{math.MinInt64, 2, 0, "-0b1" + strings.Repeat("0", 63)},
+		{math.MaxInt64, 8, 3, "0o777_777_777_777_777_777_777"},
+	} {
+		got, err := FormatBase(c.n, c.base, c.groupSize)
+		if err != nil || got != c.want {
+			t.Errorf("FormatBase(%d, %d, %d) = %q, %v, want %q", c.n, c.base, c.groupSize, got, err, c.want)
+			continue
+		}
+		if back, err := ParseBase(got); err != nil || back != c.n {
+			t.Errorf("ParseBase(%q) = %d, %v, want %d", got, back, err, c.n)
+		}
+	}
+	if _, err := FormatBase(10, 3, 0); err == nil {
+		t.Error("FormatBase in base 3 succeeded")
+	}
+	for _, s := range []string{"0x_", "0b2", "1__0", "_10", "0x8000_0000_0000_0000"} {
+		if n, err := ParseBase(s); err == nil {
+			t.Errorf("ParseBase(%q) = %d, want an error", s, n)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package numfmt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// siPrefixes run from 10^-30 to 10^30 in steps of 1000. index 10 is no prefix.
+var siPrefixes = []string{"q", "r", "y", "z", "a", "f", "p", "n", "µ", "m", "", "k", "M", "G", "T", "P", "E", "Z", "Y", "R", "Q"}
+
+// iecPrefixes are the binary ones, in steps of 1024.
+var iecPrefixes = []string{"", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei", "Zi", "Yi"}
+
+// FormatSI scales f by a power of 1000 and adds the SI prefix, keeping sigFigs significant
+// figures: FormatSI(20_000_000, 3) is "20.0M" and FormatSI(0.00042, 2) is "420µ".
+func FormatSI(f float64, sigFigs int) string {
+	if f == 0 || math.IsInf(f, 0) || math.IsNaN(f) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	// round first: 999_960 to three figures is 1.00M, not 1000k.
+	f = roundSig(f, sigFigs)
+	i := int(math.Floor(math.Log10(math.Abs(f))/3)) + 10
+	i = max(0, min(i, len(siPrefixes)-1))
+	scaled := f / math.Pow(1000, float64(i-10))
+	return FormatSig(scaled, sigFigs, Locale{Decimal: "."}) + siPrefixes[i]
+}
+
+// FormatIEC scales n by a power of 1024 and adds the binary prefix: FormatIEC(1536, 3) is
+// "1.50Ki". numbers under 1024 are written as they are.
+func FormatIEC(n float64, sigFigs int) string {
+	if math.IsInf(n, 0) || math.IsNaN(n) {
+		return strconv.FormatFloat(n, 'g', -1, 64)
+	}
+	i := 0
+	for math.Abs(n) >= 1024 && i < len(iecPrefixes)-1 {
+		n /= 1024
+		i++
+	}
+	if i == 0 && n == math.Trunc(n) {
+		return strconv.FormatFloat(n, 'f', 0, 64)
+	}
+	// rounding can carry into the next unit: 1023.9Ki to four figures is 1024Ki, which is 1.000Mi.
+	// it only carries when the rounded number really is 1024 or more; 1023.9Ki to three figures is
+	// 1020Ki, which is right as it is.
+	if r := roundSig(n, sigFigs); i < len(iecPrefixes)-1 && math.Abs(r) >= 1024 {
+		n = r / 1024
+		i++
+	}
+	return FormatSig(n, sigFigs, Locale{Decimal: "."}) + iecPrefixes[i]
+}
+
+// ParseSI reads a number with an optional SI prefix. "u" is accepted for micro.
+func ParseSI(s string) (float64, error) {
+	num, unit := splitUnit(s)
+	if unit == "u" {
+		unit = "µ"
+	}
+	for i, p := range siPrefixes {
+		if p == unit {
+			f, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return 0, err
+			}
+			return f * math.Pow(1000, float64(i-10)), nil
+		}
+	}
+	return 0, fmt.Errorf("numfmt: unknown SI prefix %q in %q", unit, s)
+}
+
+// ParseIEC reads a number with an optional binary prefix.
+func ParseIEC(s string) (float64, error) {
+	num, unit := splitUnit(s)
+	for i, p := range iecPrefixes {
+		if p == unit {
+			f, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return 0, err
+			}
+			return f * math.Pow(1024, float64(i)), nil
+		}
+	}
+	return 0, fmt.Errorf("numfmt: unknown IEC prefix %q in %q", unit, s)
+}
+
+// splitUnit splits "1.5Ki" into "1.5" and "Ki".
+func splitUnit(s string) (num, unit string) {
+	s = strings.TrimSpace(s)
+	i := strings.LastIndexAny(s, "0123456789.") + 1
+	return s[:i], strings.TrimSpace(s[i:])
+}
+
+// FormatSig writes f rounded to sigFigs significant figures without switching to exponent
+// notation. trailing zeros are kept because they're significant: FormatSig(2, 3, English) is
+// "2.00" and FormatSig(123456, 2, English) is "120,000".
+func FormatSig(f float64, sigFigs int, loc Locale) string {
+	if f == 0 || math.IsInf(f, 0) || math.IsNaN(f) || sigFigs < 1 {
+		return FormatFloat(f, max(sigFigs-1, 0), loc)
+	}
+	e := strconv.FormatFloat(f, 'e', sigFigs-1, 64)
+	mant, expStr, _ := strings.Cut(e, "e")
+	exp, _ := strconv.Atoi(expStr)
+	if exp >= sigFigs-1 {
+		// no digits after the point. the float has digits past sigFigs, but they're noise (1.23e25
+		// is 12300000000000000276824064), so the significant ones are written out and zero-filled.
+		sign := ""
+		if mant[0] == '-' {
+			sign, mant = "-", mant[1:]
+		}
+		digits := strings.Replace(mant, ".", "", 1) + strings.Repeat("0", exp-(sigFigs-1))
+		return sign + group(digits, loc.Group, loc.Sizes)
+	}
+	rounded, _ := strconv.ParseFloat(e, 64)
+	return FormatFloat(rounded, sigFigs-1-exp, loc)
+}
+
+func roundSig(f float64, sigFigs int) float64 {
+	r, _ := strconv.ParseFloat(strconv.FormatFloat(f, 'e', max(sigFigs-1, 0), 64), 64)
+	return r
+}
@@ -0,0 +1,107 @@
+package numfmt
+
+import "testing"
+
+func TestFormatIEC(t *testing.T) {
+	for _, c := range []struct {
+		n       float64
+		sigFigs int
+		want    string
+	}{
+		{0, 3, "0"},
+		{1000, 3, "1000"},
+		{1023, 3, "1023"},
+		{1536, 3, "1.50Ki"},
+		{1 << 20, 3, "1.00Mi"},
+		// rounding only carries into the next unit when it reaches 1024: to three figures everything
+		// from 1015Ki up rounds to 1020Ki, which is still in range.
+		{1015 * 1024, 2, "1000Ki"},
+		{1015 * 1024, 3, "1020Ki"},
+		{1015 * 1024, 4, "1015Ki"},
+		{1019 * 1024, 2, "1000Ki"},
+		{1019 * 1024, 3, "1020Ki"},
+		{1019 * 1024, 4, "1019Ki"},
+		{1020 * 1024, 2, "1000Ki"},
+		{1020 * 1024, 3, "1020Ki"},
+		{1020 * 1024, 4, "1020Ki"},
+		{1023 * 1024, 2, "1000Ki"},
+		{1023 * 1024, 3, "1020Ki"},
+		{1023 * 1024, 4, "1023Ki"},
+		{1023.4 * 1024, 4, "1023Ki"},
+		{1023.9 * 1024, 3, "1020Ki"},
+		{1023.9 * 1024, 4, "1.000Mi"},
+		{1024 * 1024, 2, "1.0Mi"},
+		{1024 * 1024, 3, "1.00Mi"},
+		{1024 * 1024, 4, "1.000Mi"},
+		{1<<20 - 1, 3, "1020Ki"},
+		{1<<20 - 1, 4, "1.000Mi"},
+		{1<<30 - 1, 3, "1020Mi"},
+		{-(1<<20 - 1), 4, "-1.000Mi"},
+		{-(1<<20 - 1), 3, "-1020Ki"},
+		{1023.9999, 3, "1020"},
+		{1023.9999, 4, "1.000Ki"},
+		{1 << 80, 3, "1.00Yi"},
+		{1 << 90, 3, "1020Yi"},
+	} {
+		if got := FormatIEC(c.n, c.sigFigs); got != c.want {
+			t.Errorf("FormatIEC(%v, %d) = %q, want %q", c.n, c.sigFigs, got, c.want)
+		}
+	}
+}
+
+func TestFormatSI(t *testing.T) {
+	for _, c := range []struct {
+		f       float64
+		sigFigs int
+		want    string
+	}{
+		{20_000_000, 3, "20.0M"},
+		{0.00042, 2, "420µ"},
+		{999_960, 3, "1.00M"},
+		{999_999_999, 3, "1.00G"},
+		{-1500, 2, "-1.5k"},
+	} {
+		if got := FormatSI(c.f, c.sigFigs); got != c.want {
+			t.Errorf("FormatSI(%v, %d) = %q, want %q", c.f, c.sigFigs, got, c.want)
+		}
+	}
+}
+
+func TestParseIECRoundTrip(t *testing.T) {
+	for _, s := range []string{"1.50Ki", "1.00Mi", "3Gi", "512"} {
+		f, err := ParseIEC(s)
+		if err != nil {
+			t.Fatalf("ParseIEC(%q): %v", s, err)
+		}
+		if got := FormatIEC(f, 3); got != s && !(s == "3Gi" && got == "3.00Gi") {
+			t.Errorf("FormatIEC(ParseIEC(%q)) = %q", s, got)
+		}
+	}
+}
+
+func TestFormatSig(t *testing.T) {
+	for _, c := range []struct {
+		f       float64
+		sigFigs int
+		loc     Locale
+		want    string
+	}{
+		{2, 3, English, "2.00"},
+		{123456, 2, English, "120,000"},
+		{123456, 6, English, "123,456"},
+		{123456, 8, English, "123,456.00"},
+		{0.000123456, 2, German, "0,00012"},
+		{-9.996, 3, English, "-10.0"},
+		{999.5, 3, English, "1,000"},
+		{0, 3, English, "0.00"},
+		// the float's digits past the third are noise, and mustn't show.
+		{1.23456e25, 3, English, "12,300,000,000,000,000,000,000,000"},
+		{-1.23456e25, 3, Indian, "-1,23,00,00,00,00,00,00,00,00,00,00,000"},
+		{1e22, 1, Go, "10_000_000_000_000_000_000_000"},
+		{1 << 62, 4, French, "4\u202f612\u202f000\u202f000\u202f000\u202f000\u202f000"},
+	} {
+		if got := FormatSig(c.f, c.sigFigs, c.loc); got != c.want {
+			t.Errorf("FormatSig(%v, %d) = %q, want %q", c.f, c.sigFigs, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Package panics turns panics into errors you can look at.
+//
+// types() says dividing by zero "causes a panic (whatever tf this is)" and mapsInGo() says writing
+// to a nil map panics. a panic unwinds the stack until something calls recover() or the program
+// dies. Run does the recovering: it calls a function and, if it panics, returns an *Error with the
+// value that was passed to panic, what kind of runtime failure it was and where it happened.
+package panics
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Kind classifies what caused a panic.
+type Kind int
+
+const (
+	Custom           Kind = iota // panic(v) called by the program itself
+	DivideByZero                 // integer divide by zero
+	NilMapWrite                  // assignment to entry in nil map
+	IndexOutOfRange              // a[i] with i outside 0..len(a)-1
+	SliceBounds                  // a[i:j] outside the capacity
+	ConversionLength             // [N]T(s) with len(s) < N
+	NilDereference               // *p or p.f with p == nil
+	TypeAssertion                // x.(T) without the comma-ok form
+	ClosedChannel                // close or send on a closed channel, or close of a nil one
+	OtherRuntime                 // any other runtime.Error
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Custom:
+		return "custom panic"
+	case DivideByZero:
+		return "divide by zero"
+	case NilMapWrite:
+		return "nil map write"
+	case IndexOutOfRange:
+		return "index out of range"
+	case SliceBounds:
+		return "slice bounds out of range"
+	case ConversionLength:
+		return "conversion length"
+	case NilDereference:
+		return "nil dereference"
+	case TypeAssertion:
+		return "type assertion"
+	case ClosedChannel:
+		return "closed channel"
+	case OtherRuntime:
+		return "runtime error"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Frame is one call in the stack of a recovered panic.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (f Frame) String() string { return fmt.Sprintf("%s\n\t%s:%d", f.Function, f.File, f.Line) }
+
+// Error is a recovered panic.
+type Error struct {
+	Value any     // what was passed to panic
+	Kind  Kind    // what caused it
+	Stack []Frame // where it happened, innermost call first, without runtime and recovery frames
+}
+
+func (e *Error) Error() string { return fmt.Sprintf("panic: %v", e.Value) }
+
+// Unwrap exposes the panic value when it is itself an error, so errors.Is and errors.As see
+// through to runtime.Error or whatever the program panicked with.
+func (e *Error) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Report is the error, its kind and the cleaned stack trace, ready to print.
+func (e *Error) Report() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)\n", e.Error(), e.Kind)
+	for _, f := range e.Stack {
+		fmt.Fprintf(&b, "  %s\n", strings.ReplaceAll(f.String(), "\n", "\n  "))
+	}
+	return b.String()
+}
+
+// Run calls f and returns nil if it returns normally, or an *Error if it panics.
+func Run(f func()) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = newError(v)
+		}
+	}()
+	f()
+	return nil
+}
+
+// Call is Run for functions that return a value and an error. a panic comes back as an *Error
+// and the zero value.
+func Call[T any](f func() (T, error)) (v T, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			var zero T
+			v, err = zero, newError(p)
+		}
+	}()
+	return f()
+}
+
+// As reports whether err is a recovered panic, and returns it.
+func As(err error) (*Error, bool) {
+	var pe *Error
+	ok := errors.As(err, &pe)
+	return pe, ok
+}
+
+func newError(v any) *Error {
+	return &Error{Value: v, Kind: classify(v), Stack: stack()}
+}
+
+// classify goes by the runtime's messages, which are the only thing that tells its panics apart.
+func classify(v any) Kind {
+	re, ok := v.(runtime.Error)
+	if !ok {
+		return Custom
+	}
+	var tae *runtime.TypeAssertionError
+	if errors.As(re, &tae) {
+		return TypeAssertion
+	}
+
+	msg := strings.TrimPrefix(re.Error(), "runtime error: ")
+	switch {
+	case strings.HasPrefix(msg, "integer divide by zero"):
+		return DivideByZero
+	case strings.HasPrefix(msg, "assignment to entry in nil map"):
+		return NilMapWrite
+	case strings.HasPrefix(msg, "index out of range"):
+		return IndexOutOfRange
+	case strings.HasPrefix(msg, "slice bounds out of range"):
+		return SliceBounds
+	case strings.HasPrefix(msg, "cannot convert slice with length"):
+		return ConversionLength
+	case strings.HasPrefix(msg, "invalid memory address or nil pointer dereference"):
+		return NilDereference
+	}
+	// these three don't carry the "runtime error: " prefix, but are runtime.Errors all the same.
+	switch msg {
+	case "close of closed channel", "close of nil channel", "send on closed channel":
+		return ClosedChannel
+	}
+	return OtherRuntime
+}
+
+// stack is called from the deferred function, so the frames above the panic are still there.
+// the runtime's own frames and this package's are dropped, which leaves the panicking function
+// first.
+func stack() []Frame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var out []Frame
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "runtime.") && !isOwnFrame(f.Function) {
+			out = append(out, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		}
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+func isOwnFrame(fn string) bool { return strings.HasPrefix(fn, ownPath+".") }
+
+// ownPath is this package's import path, worked out at run time so the filter survives a module
+// rename.
+var ownPath = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	fn := runtime.FuncForPC(pc).Name() // e.g. ch_02/panics.init.func1
+	slash := strings.LastIndex(fn, "/")
+	return fn[:slash+1+strings.Index(fn[slash+1:], ".")]
+}()
@@ -0,0 +1,128 @@
+// the tests live outside the package: stack() drops every frame from ch_02/panics, and the
+// panicking functions here have to show up in the stack.
+package panics_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"ch_02/panics"
+)
+
+// each of these panics in its own frame, so the test can check the stack starts there. the
+// operands come in as arguments so the compiler can't reject or fold them.
+
+//go:noinline
+func nilMapWrite(m map[string]int) (int, error) { m["wtf"] = 3; return 0, nil }
+
+//go:noinline
+func indexOutOfRange(s []int, i int) (int, error) { return s[i], nil }
+
+//go:noinline
+func nilDereference(p *struct{ n int }) (int, error) { return p.n, nil }
+
+//go:noinline
+func badAssertion(v any) (int, error) { return v.(int), nil }
+
+//go:noinline
+func divideByZero(x, y int) (int, error) { return x / y, nil }
+
+//go:noinline
+func closeClosed(ch chan int) (int, error) { close(ch); close(ch); return 0, nil }
+
+//go:noinline
+func sliceBounds(s []int, j int) (int, error) { return len(s[:j]), nil }
+
+//go:noinline
+func custom() (int, error) { panic("whatever tf this is") }
+
+func TestCall(t *testing.T) {
+	for _, c := range []struct {
+		kind panics.Kind
+		fn   string
+		f    func() (int, error)
+	}{
+		{panics.NilMapWrite, "nilMapWrite", func() (int, error) { return nilMapWrite(nil) }},
+		{panics.IndexOutOfRange, "indexOutOfRange", func() (int, error) { return indexOutOfRange([]int{1, 2, 3}, 3) }},
+		{panics.NilDereference, "nilDereference", func() (int, error) { return nilDereference(nil) }},
+		{panics.TypeAssertion, "badAssertion", func() (int, error) { return badAssertion("hello") }},
+		{panics.DivideByZero, "divideByZero", func() (int, error) { return divideByZero(10, 0) }},
+		{panics.ClosedChannel, "closeClosed", func() (int, error) { return closeClosed(make(chan int)) }},
+		{panics.SliceBounds, "sliceBounds", func() (int, error) { return sliceBounds(make([]int, 2, 4), 5) }},
+		{panics.Custom, "custom", custom},
+	} {
+		t.Run(c.kind.String(), func(t *testing.T) {
+			v, err := panics.Call(c.f)
+			if v != 0 {
+				t.Errorf("Call returned %d, want the zero value", v)
+			}
+			pe, ok := panics.As(err)
+			if !ok {
+				t.Fatalf("Call = %v, want an *Error", err)
+			}
+			if pe.Kind != c.kind {
+				t.Errorf("Kind = %s, want %s (%v)", pe.Kind, c.kind, pe.Value)
+			}
+			if c.kind != panics.Custom {
+				var re runtime.Error
+				if !errors.As(err, &re) {
+					t.Errorf("errors.As(%v, runtime.Error) failed", err)
+				}
+			}
+
+			// the stack starts where the panic did and has nothing from the runtime or the
+			// recovery in it.
+			if len(pe.Stack) == 0 {
+				t.Fatal("empty stack")
+			}
+			if top := pe.Stack[0].Function; !strings.HasSuffix(top, "."+c.fn) {
+				t.Errorf("stack starts at %s, want %s\n%s", top, c.fn, pe.Report())
+			}
+			if !strings.HasSuffix(pe.Stack[0].File, "panics_test.go") || pe.Stack[0].Line == 0 {
+				t.Errorf("top frame is at %s:%d", pe.Stack[0].File, pe.Stack[0].Line)
+			}
+			for _, f := range pe.Stack {
+				if strings.HasPrefix(f.Function, "runtime.") || strings.HasPrefix(f.Function, "ch_02/panics.") {
+					t.Errorf("stack has %s in it", f.Function)
+				}
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	if err := panics.Run(func() {}); err != nil {
+		t.Errorf("Run of a function that returns = %v", err)
+	}
+	sentinel := errors.New("boom")
+	err := panics.Run(func() { panic(fmt.Errorf("wrapped: %w", sentinel)) })
+	if !errors.Is(err, sentinel) {
+		t.Errorf("errors.Is(%v, sentinel) = false", err)
+	}
+	if err.Error() != "panic: wrapped: boom" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if _, ok := panics.As(sentinel); ok {
+		t.Error("As found a panic in an ordinary error")
+	}
+}
+
+func TestCallPassesThrough(t *testing.T) {
+	sentinel := errors.New("not a panic")
+	v, err := panics.Call(func() (string, error) { return "x", sentinel })
+	if v != "x" || err != sentinel {
+		t.Errorf("Call = %q, %v", v, err)
+	}
+}
+
+func TestReport(t *testing.T) {
+	_, err := panics.Call(func() (int, error) { return divideByZero(1, 0) })
+	pe, _ := panics.As(err)
+	r := pe.Report()
+	if !strings.HasPrefix(r, "panic: runtime error: integer divide by zero (divide by zero)\n  ch_02/panics_test.divideByZero\n  \t") {
+		t.Errorf("Report() =\n%s", r)
+	}
+}
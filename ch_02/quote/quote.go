@@ -0,0 +1,205 @@
+// Package quote writes Go string and rune literals in whichever form you ask for.
+//
+// literals() lists 'a', '\141', '\x61', '\u0061' and '\U00000061' as the same rune and contrasts
+// interpreted "..." strings with raw `...` ones. strconv.Quote only ever picks one spelling; Quote
+// here takes a Style, so the same value can be written every way the language allows.
+package quote
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Style is how a literal is spelled.
+type Style int
+
+const (
+	// Minimal is an interpreted literal that escapes only what it must: the quote character,
+	// backslash, control characters and invalid UTF-8. everything printable is written as is.
+	Minimal Style = iota
+	// ASCII escapes every non-ASCII rune, with \u or \U, like strconv.QuoteToASCII.
+	ASCII
+	// Hex escapes every byte as \x.., so a rune takes as many escapes as it has UTF-8 bytes.
+	Hex
+	// Octal escapes every byte as \...
+	Octal
+	// Unicode escapes every rune as \u.... (or \U........ above U+FFFF).
+	Unicode
+	// LongUnicode escapes every rune as \U.........
+	LongUnicode
+	// Raw is a backquoted literal, falling back to Minimal when the value can't be written raw.
+	Raw
+)
+
+func (s Style) String() string {
+	switch s {
+	case Minimal:
+		return "minimal"
+	case ASCII:
+		return "ascii"
+	case Hex:
+		return "hex"
+	case Octal:
+		return "octal"
+	case Unicode:
+		return "unicode"
+	case LongUnicode:
+		return "longunicode"
+	case Raw:
+		return "raw"
+	}
+	return fmt.Sprintf("Style(%d)", int(s))
+}
+
+// ParseStyle is the inverse of Style.String.
+func ParseStyle(name string) (Style, error) {
+	for s := Minimal; s <= Raw; s++ {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("quote: unknown style %q", name)
+}
+
+// CanRaw reports whether s can be written as a raw string literal. raw literals can't contain a
+// backquote, and since the compiler drops carriage returns from them they can't hold \r either.
+// invalid UTF-8 and a byte order mark aren't allowed in source at all.
+func CanRaw(s string) bool {
+	return utf8.ValidString(s) &&
+		!strings.ContainsAny(s, "`\r\uFEFF") &&
+		!strings.ContainsFunc(s, func(r rune) bool {
+			return r == 0 || (unicode.IsControl(r) && r != '\n' && r != '\t')
+		})
+}
+
+// String writes s as a string literal in the given style.
+func String(s string, style Style) string {
+	if style == Raw {
+		if CanRaw(s) {
+			return "`" + s + "`"
+		}
+		style = Minimal
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			// a stray byte can only be written as a byte escape.
+			if style == Octal {
+				fmt.Fprintf(&b, `\%03o`, s[i])
+			} else {
+				fmt.Fprintf(&b, `\x%02x`, s[i])
+			}
+		} else {
+			writeRune(&b, s[i:i+size], r, '"', style)
+		}
+		i += size
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// Rune writes r as a rune literal in the given style. Raw doesn't exist for runes and is treated
+// as Minimal. runes that aren't valid Unicode (surrogates, > U+10FFFF) have no literal, and runes
+// above U+00FF have no Hex or Octal form, so those are errors.
+func Rune(r rune, style Style) (string, error) {
+	if !utf8.ValidRune(r) {
+		return "", fmt.Errorf("quote: %U is not a valid rune", r)
+	}
+	if style == Raw {
+		style = Minimal
+	}
+	var b strings.Builder
+	b.WriteByte('\'')
+	switch {
+	case (style == Hex || style == Octal) && r > 0xFF:
+		// in a rune literal '\xNN' is the rune NN, not a UTF-8 byte, so only the first 256 runes
+		// have a byte escape.
+		return "", fmt.Errorf("quote: %U has no %s escape in a rune literal", r, style)
+	case style == Hex:
+		fmt.Fprintf(&b, `\x%02x`, r)
+	case style == Octal:
+		fmt.Fprintf(&b, `\%03o`, r)
+	default:
+		writeRune(&b, string(r), r, '\'', style)
+	}
+	b.WriteByte('\'')
+	return b.String(), nil
+}
+
+func writeRune(b *strings.Builder, enc string, r rune, quote byte, style Style) {
+	switch style {
+	case Hex:
+		for i := range len(enc) {
+			fmt.Fprintf(b, `\x%02x`, enc[i])
+		}
+		return
+	case Octal:
+		for i := range len(enc) {
+			fmt.Fprintf(b, `\%03o`, enc[i])
+		}
+		return
+	case Unicode:
+		writeUnicode(b, r)
+		return
+	case LongUnicode:
+		fmt.Fprintf(b, `\U%08x`, r)
+		return
+	}
+
+	// Minimal and ASCII share the short escapes.
+	switch r {
+	case '\a':
+		b.WriteString(`\a`)
+	case '\b':
+		b.WriteString(`\b`)
+	case '\f':
+		b.WriteString(`\f`)
+	case '\n':
+		b.WriteString(`\n`)
+	case '\r':
+		b.WriteString(`\r`)
+	case '\t':
+		b.WriteString(`\t`)
+	case '\v':
+		b.WriteString(`\v`)
+	case '\\':
+		b.WriteString(`\\`)
+	default:
+		switch {
+		case r == rune(quote):
+			b.WriteByte('\\')
+			b.WriteByte(quote)
+		case r < utf8.RuneSelf && unicode.IsPrint(r):
+			b.WriteRune(r)
+		case r < utf8.RuneSelf:
+			fmt.Fprintf(b, `\x%02x`, r)
+		case style == ASCII || !strconv.IsPrint(r):
+			writeUnicode(b, r)
+		default:
+			b.WriteString(enc)
+		}
+	}
+}
+
+func writeUnicode(b *strings.Builder, r rune) {
+	if r > 0xFFFF {
+		fmt.Fprintf(b, `\U%08x`, r)
+		return
+	}
+	fmt.Fprintf(b, `\u%04x`, r)
+}
+
+// Every returns s written in every style, which is a quick way to see all the spellings at once.
+func Every(s string) map[Style]string {
+	out := map[Style]string{}
+	for style := Minimal; style <= Raw; style++ {
+		out[style] = String(s, style)
+	}
+	return out
+}
@@ -0,0 +1,239 @@
+package quote
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	for _, c := range []struct {
+		in    string
+		style Style
+		want  string
+	}{
+		// literals() writes 'a' five ways; these are the string versions.
+		{"a", Minimal, `"a"`},
+		{"a", ASCII, `"a"`},
+		{"a", Hex, `"\x61"`},
+		{"a", Octal, `"\141"`},
+		{"a", Unicode, `"\u0061"`},
+		{"a", LongUnicode, `"\U00000061"`},
+		{"a", Raw, "`a`"},
+
+		{"é\t\"😀", Minimal, `"é\t\"😀"`},
+		{"é\t\"😀", ASCII, `"\u00e9\t\"\U0001f600"`},
+		{"é\t\"😀", Hex, `"\xc3\xa9\x09\x22\xf0\x9f\x98\x80"`},
+		{"é\t\"😀", Octal, `"\303\251\011\042\360\237\230\200"`},
+		{"é\t\"😀", Unicode, `"\u00e9\u0009\u0022\U0001f600"`},
+		{"é\t\"😀", LongUnicode, `"\U000000e9\U00000009\U00000022\U0001f600"`},
+		{"é\t\"😀", Raw, "`é\t\"😀`"},
+
+		{"\a\b\f\n\r\v\\\x00\x7f", Minimal, `"\a\b\f\n\r\v\\\x00\x7f"`},
+		{"\u200b", Minimal, `"\u200b"`}, // not printable, so escaped even in Minimal
+		{"'", Minimal, `"'"`},
+		{"bad\xffbyte", Minimal, `"bad\xffbyte"`},
+		{"bad\xffbyte", ASCII, `"bad\xffbyte"`},
+		{"bad\xffbyte", Unicode, `"\u0062\u0061\u0064\xff\u0062\u0079\u0074\u0065"`},
+		{"bad\xff", Octal, `"\142\141\144\377"`},
+		{"a`b", Raw, "\"a`b\""}, // can't be raw: falls back to Minimal
+		{"a\rb", Raw, `"a\rb"`},
+		{"", Raw, "``"},
+		{"", Hex, `""`},
+	} {
+		if got := String(c.in, c.style); got != c.want {
+			t.Errorf("String(%q, %s) = %s, want %s", c.in, c.style, got, c.want)
+		}
+	}
+}
+
+// TestStringRoundTrip checks that every style of every value unquotes back to that value.
+func TestStringRoundTrip(t *testing.T) {
+	for _, s := range []string{"", "a", "héllo, 世界", "tab\there\nline", "\x00\x01\x7f", "bad\xff\xfe", "`back`", "\uFEFFbom", "\U0010FFFF", "\\\"'"} {
+		for style, lit := range Every(s) {
+			got, err := strconv.Unquote(lit)
+			if err != nil || got != s {
+				t.Errorf("%s of %q is %s, which unquotes to %q, %v", style, s, lit, got, err)
+			}
+		}
+	}
+}
+
+func TestRune(t *testing.T) {
+	for _, c := range []struct {
+		r     rune
+		style Style
+		want  string
+	}{
+		{'a', Minimal, `'a'`},
+		{'a', ASCII, `'a'`},
+		{'a', Hex, `'\x61'`},
+		{'a', Octal, `'\141'`},
+		{'a', Unicode, `'\u0061'`},
+		{'a', LongUnicode, `'\U00000061'`},
+		{'a', Raw, `'a'`},
+		{'\'', Minimal, `'\''`},
+		{'"', Minimal, `'"'`},
+		{'\n', Minimal, `'\n'`},
+		{'é', Minimal, `'é'`},
+		{'é', ASCII, `'\u00e9'`},
+		{'é', Hex, `'\xe9'`}, // the rune, not its UTF-8 bytes
+		{'é', Octal, `'\351'`},
+		{'😀', Unicode, `'\U0001f600'`},
+	} {
+		got, err := Rune(c.r, c.style)
+		if err != nil || got != c.want {
+			t.Errorf("Rune(%q, %s) = %s, %v, want %s", c.r, c.style, got, err, c.want)
+			continue
+		}
+		if back, _, _, err := strconv.UnquoteChar(got[1:len(got)-1], '\''); err != nil || back != c.r {
+			t.Errorf("%s unquotes to %q, %v", got, back, err)
+		}
+	}
+	for _, c := range []struct {
+		r     rune
+		style Style
+	}{
+		{0xD800, Minimal}, // a surrogate
+		{0x110000, Unicode},
+		{-1, Minimal},
+		{'€', Hex},
+		{'€', Octal},
+		{0x100, Hex},
+	} {
+		if got, err := Rune(c.r, c.style); err == nil {
+			t.Errorf("Rune(%#x, %s) = %s, want an error", c.r, c.style, got)
+		}
+	}
+}
+
+func TestCanRaw(t *testing.T) {
+	for s, want := range map[string]bool{
+		"plain":          true,
+		"":               true,
+		"tab\tnewline\n": true,
+		"héllo":          true,
+		"back`quote":     false,
+		"carriage\r":     false,
+		"bad\xff":        false,
+		"\uFEFFbom":      false,
+		"nul\x00":        false,
+		"bell\a":         false,
+	} {
+		if got := CanRaw(s); got != want {
+			t.Errorf("CanRaw(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	for s := Minimal; s <= Raw; s++ {
+		if got, err := ParseStyle(s.String()); err != nil || got != s {
+			t.Errorf("ParseStyle(%q) = %v, %v", s.String(), got, err)
+		}
+	}
+	if _, err := ParseStyle("fancy"); err == nil {
+		t.Error("ParseStyle(\"fancy\") succeeded")
+	}
+}
+
+const rewriteSrc = "package p\n" + `
+
+import "fmt"
+
+type T struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+// the spacing here isn't gofmt's, on purpose: RewriteFile mustn't touch it.
+func f() {
+	a   :=  "a\x62c"   // short
+	b := "héllo\tworld" // longer
+	c := ` + "`raw`" + `
+	d := "back` + "`" + `quote"
+	e := 'x'
+	fmt.Println(a,b, c, d, e, "é")
+}
+`
+
+func TestRewriteFile(t *testing.T) {
+	for style := Minimal; style <= Raw; style++ {
+		t.Run(style.String(), func(t *testing.T) {
+			out, n, err := RewriteFile("p.go", []byte(rewriteSrc), style)
+			if err != nil {
+				t.Fatal(err)
+			}
+			before, after := literals(t, rewriteSrc), literals(t, string(out))
+			if len(before) != len(after) {
+				t.Fatalf("%d literals before, %d after:\n%s", len(before), len(after), out)
+			}
+			changed := 0
+			var gaps []string
+			prev := 0
+			for i := range before {
+				bv, _ := strconv.Unquote(before[i].text)
+				av, err := strconv.Unquote(after[i].text)
+				if err != nil || av != bv {
+					t.Errorf("%s became %s, which is %q, not %q", before[i].text, after[i].text, av, bv)
+				}
+				if before[i].text != after[i].text {
+					changed++
+				}
+				gaps = append(gaps, rewriteSrc[prev:before[i].start])
+				prev = before[i].end
+			}
+			gaps = append(gaps, rewriteSrc[prev:])
+			if changed != n {
+				t.Errorf("RewriteFile says %d changed, %d did", n, changed)
+			}
+
+			// everything between the literals is byte for byte what it was.
+			prev = 0
+			for i, lit := range after {
+				if got := string(out[prev:lit.start]); got != gaps[i] {
+					t.Errorf("code before literal %d changed from %q to %q", i, gaps[i], got)
+				}
+				prev = lit.end
+			}
+			if got := string(out[prev:]); got != gaps[len(gaps)-1] {
+				t.Errorf("code after the last literal changed from %q to %q", gaps[len(gaps)-1], got)
+			}
+			if !strings.Contains(string(out), `import "fmt"`) || !strings.Contains(string(out), "`json:\"name\"`") {
+				t.Errorf("import path or struct tag was rewritten:\n%s", out)
+			}
+		})
+	}
+}
+
+type lit struct {
+	text       string
+	start, end int
+}
+
+// literals returns the string literals in src in order, except the import path and struct tag,
+// which RewriteFile leaves alone.
+func literals(t *testing.T, src string) []lit {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatalf("%v in\n%s", err, src)
+	}
+	var out []lit
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ImportSpec, *ast.Field:
+			return false
+		case *ast.BasicLit:
+			if n.Kind == token.STRING {
+				start := fset.Position(n.Pos()).Offset
+				out = append(out, lit{n.Value, start, start + len(n.Value)})
+			}
+		}
+		return true
+	})
+	return out
+}
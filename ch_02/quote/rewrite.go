@@ -0,0 +1,71 @@
+package quote
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+)
+
+// RewriteFile re-spells every string literal in a Go source file in the given style and returns
+// the new source. the values don't change: each literal is unquoted and quoted again, and only
+// replaced if the new spelling is different. import paths and struct tags are left alone, since
+// they have spellings of their own by convention. the second result is how many literals changed.
+//
+// with Raw, literals that can't be raw (they contain a backquote or a control character) are kept
+// as they are rather than being rewritten in Minimal.
+//
+// nothing but the literals changes, not even the formatting around them. a literal that gets
+// longer or shorter can leave the comments after it out of line; run gofmt on the result for that.
+func RewriteFile(filename string, src []byte, style Style) ([]byte, int, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := map[*ast.BasicLit]bool{}
+	for _, imp := range file.Imports {
+		skip[imp.Path] = true
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Field:
+			if n.Tag != nil {
+				skip[n.Tag] = true
+			}
+		case *ast.BasicLit:
+			if n.Kind != token.STRING || skip[n] {
+				return true
+			}
+			value, err := strconv.Unquote(n.Value)
+			if err != nil {
+				return true
+			}
+			if style == Raw && !CanRaw(value) {
+				return true
+			}
+			if next := String(value, style); next != n.Value {
+				start := fset.Position(n.Pos()).Offset
+				edits = append(edits, edit{start, start + len(n.Value), next})
+			}
+		}
+		return true
+	})
+
+	// apply from the end so earlier offsets stay valid.
+	slices.SortFunc(edits, func(a, b edit) int { return b.start - a.start })
+	out := bytes.Clone(src)
+	for _, e := range edits {
+		out = slices.Concat(out[:e.start], []byte(e.text), out[e.end:])
+	}
+	return out, len(edits), nil
+}
@@ -0,0 +1,227 @@
+package rational
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Parse reads a fraction ("10/3", "-7/2"), a decimal ("0.1", "-1.25") or a number in scientific
+// notation ("1e-3"). decimals are read exactly, so Parse("0.1") is 1/10, not the float 0.1.
+func Parse(s string) (Rational, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return Rational{}, fmt.Errorf("rational: cannot parse %q", s)
+	}
+	return fromBig(r), nil
+}
+
+// FromFloat64 returns the exact value of f. every finite float is a fraction with a power of two
+// below it, so this never loses anything: FromFloat64(0.1) is 3602879701896397/36028797018963968.
+func FromFloat64(f float64) (Rational, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return Rational{}, errors.New("rational: cannot represent Inf or NaN")
+	}
+	return fromBig(new(big.Rat).SetFloat64(f)), nil
+}
+
+// Float64 returns the nearest float64 and whether it is exactly r.
+func (r Rational) Float64() (f float64, exact bool) {
+	if num, den, ok := r.parts(); ok && abs64(num) <= 1<<53 && den <= 1<<53 {
+		// both fit in a float64's mantissa, so a single division rounds correctly.
+		f = float64(num) / float64(den)
+		return f, den&(den-1) == 0
+	}
+	return r.Big().Float64()
+}
+
+// Decimal returns r as a decimal, and whether that decimal is exact. only fractions whose
+// denominator has no prime factors but 2 and 5 end; the rest are cut off (and rounded) after
+// maxDigits digits past the point.
+func (r Rational) Decimal(maxDigits int) (s string, exact bool) {
+	digits, ok := terminatingDigits(r.Denom())
+	if ok && digits <= maxDigits {
+		return trimZeros(r.Big().FloatString(digits)), true
+	}
+	return r.Big().FloatString(maxDigits), false
+}
+
+// terminatingDigits reports whether 1/den has a finite decimal expansion and how many digits it
+// takes: den = 2^a * 5^b needs max(a, b) digits.
+func terminatingDigits(den *big.Int) (int, bool) {
+	d := new(big.Int).Set(den)
+	twos := int(d.TrailingZeroBits())
+	d.Rsh(d, uint(twos))
+	fives := 0
+	five, mod := big.NewInt(5), new(big.Int)
+	for {
+		q, m := new(big.Int).QuoRem(d, five, mod)
+		if m.Sign() != 0 {
+			break
+		}
+		d, fives = q, fives+1
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+	return max(twos, fives), true
+}
+
+// RepeatingDecimal writes r with the repeating part of its expansion in parentheses, so 1/3 is
+// "0.(3)" and 1/6 is "0.1(6)". the repetend can be as long as the denominator, so it gives up
+// after maxDigits digits and returns false.
+func (r Rational) RepeatingDecimal(maxDigits int) (string, bool) {
+	num, den := r.Num(), r.Denom()
+	var b strings.Builder
+	if num.Sign() < 0 {
+		b.WriteByte('-')
+		num.Neg(num)
+	}
+	intPart, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	b.WriteString(intPart.String())
+	if rem.Sign() == 0 {
+		return b.String(), true
+	}
+
+	// long division: the digits start repeating the moment a remainder comes back.
+	seen := map[string]int{}
+	var frac []byte
+	ten := big.NewInt(10)
+	for rem.Sign() != 0 {
+		key := rem.String()
+		if at, ok := seen[key]; ok {
+			b.WriteByte('.')
+			b.Write(frac[:at])
+			b.WriteByte('(')
+			b.Write(frac[at:])
+			b.WriteByte(')')
+			return b.String(), true
+		}
+		if len(frac) == maxDigits {
+			b.WriteByte('.')
+			b.Write(frac)
+			return b.String(), false
+		}
+		seen[key] = len(frac)
+		rem.Mul(rem, ten)
+		d, m := new(big.Int).QuoRem(rem, den, new(big.Int))
+		frac = append(frac, byte('0'+d.Int64()))
+		rem = m
+	}
+	b.WriteByte('.')
+	b.Write(frac)
+	return b.String(), true
+}
+
+// ContinuedFraction returns the terms [a0; a1, a2, ...] with r = a0 + 1/(a1 + 1/(a2 + ...)).
+// a0 can be negative; the rest are positive.
+func (r Rational) ContinuedFraction() []*big.Int {
+	num, den := r.Num(), r.Denom()
+	var terms []*big.Int
+	for den.Sign() != 0 {
+		// floor division keeps every term after the first positive.
+		q, m := new(big.Int).DivMod(num, den, new(big.Int))
+		terms = append(terms, q)
+		num, den = den, m
+	}
+	return terms
+}
+
+// FromContinuedFraction rebuilds a value from its terms.
+func FromContinuedFraction(terms []*big.Int) Rational {
+	if len(terms) == 0 {
+		return Rational{}
+	}
+	v := new(big.Rat).SetInt(terms[len(terms)-1])
+	for i := len(terms) - 2; i >= 0; i-- {
+		v.Inv(v)
+		v.Add(v, new(big.Rat).SetInt(terms[i]))
+	}
+	return fromBig(v)
+}
+
+// Convergents returns the successive best approximations h/k you get by cutting the continued
+// fraction short. the last one is r itself.
+func (r Rational) Convergents() []Rational {
+	var out []Rational
+	h0, h1 := big.NewInt(0), big.NewInt(1)
+	k0, k1 := big.NewInt(1), big.NewInt(0)
+	for _, a := range r.ContinuedFraction() {
+		h0, h1 = h1, new(big.Int).Add(new(big.Int).Mul(a, h1), h0)
+		k0, k1 = k1, new(big.Int).Add(new(big.Int).Mul(a, k1), k0)
+		out = append(out, fromBig(new(big.Rat).SetFrac(h1, k1)))
+	}
+	return out
+}
+
+// Approximate returns the fraction closest to r whose denominator is at most maxDen. it checks the
+// convergents and the semiconvergents between them, which between them always contain the answer.
+func (r Rational) Approximate(maxDen int64) Rational {
+	if maxDen < 1 {
+		panic("rational: maxDen must be at least 1")
+	}
+	limit := big.NewInt(maxDen)
+	if r.Denom().Cmp(limit) <= 0 {
+		return r
+	}
+
+	target := r.Big()
+	h0, h1 := big.NewInt(0), big.NewInt(1)
+	k0, k1 := big.NewInt(1), big.NewInt(0)
+	for _, a := range r.ContinuedFraction() {
+		k2 := new(big.Int).Add(new(big.Int).Mul(a, k1), k0)
+		if k2.Cmp(limit) > 0 {
+			// the next convergent is too big. the best semiconvergent uses the largest t with
+			// t*k1 + k0 <= maxDen; it only beats the last convergent if it's closer.
+			t := new(big.Int).Quo(new(big.Int).Sub(limit, k0), k1)
+			semi := new(big.Rat).SetFrac(
+				new(big.Int).Add(new(big.Int).Mul(t, h1), h0),
+				new(big.Int).Add(new(big.Int).Mul(t, k1), k0),
+			)
+			conv := new(big.Rat).SetFrac(h1, k1)
+			if distance(semi, target).Cmp(distance(conv, target)) < 0 {
+				return fromBig(semi)
+			}
+			return fromBig(conv)
+		}
+		h0, h1 = h1, new(big.Int).Add(new(big.Int).Mul(a, h1), h0)
+		k0, k1 = k1, k2
+	}
+	return r
+}
+
+// ApproximateFloat finds the simplest-looking fraction near f, e.g. ApproximateFloat(0.1, 1000) is
+// 1/10 and ApproximateFloat(math.Pi, 1000) is 355/113.
+func ApproximateFloat(f float64, maxDen int64) (Rational, error) {
+	r, err := FromFloat64(f)
+	if err != nil {
+		return Rational{}, err
+	}
+	return r.Approximate(maxDen), nil
+}
+
+func distance(a, b *big.Rat) *big.Rat {
+	d := new(big.Rat).Sub(a, b)
+	return d.Abs(d)
+}
+
+func trimZeros(s string) string {
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// MarshalText and UnmarshalText use the "num/den" form, so a Rational survives JSON exactly.
+func (r Rational) MarshalText() ([]byte, error) { return []byte(r.String()), nil }
+
+func (r *Rational) UnmarshalText(text []byte) error {
+	p, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*r = p
+	return nil
+}
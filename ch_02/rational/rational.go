@@ -0,0 +1,238 @@
+// Package rational is an exact fraction type for the results types() says need a float.
+//
+// 10/3 in integer division is 3, and float64(10)/3 is 3.3333333333333335. a Rational keeps it as
+// 10/3. numerator and denominator are int64 while they fit; when a result would overflow, the
+// value moves to a math/big.Rat and stays exact. the zero value is 0.
+package rational
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// Rational is an immutable fraction, always kept in lowest terms with a positive denominator.
+type Rational struct {
+	num, den int64    // den == 0 means the zero value, which is read as 0/1
+	big      *big.Rat // set once the value no longer fits in int64s
+}
+
+// New returns num/den in lowest terms. it panics if den is zero, like integer division does.
+func New(num, den int64) Rational {
+	if den == 0 {
+		panic("rational: zero denominator")
+	}
+	return normalize(num, den)
+}
+
+// FromInt returns n/1.
+func FromInt(n int64) Rational { return Rational{num: n, den: 1} }
+
+// FromBig copies a big.Rat, shrinking it back to int64s if it fits.
+func FromBig(r *big.Rat) Rational { return fromBig(new(big.Rat).Set(r)) }
+
+func fromBig(r *big.Rat) Rational {
+	if r.Num().IsInt64() && r.Denom().IsInt64() {
+		return Rational{num: r.Num().Int64(), den: r.Denom().Int64()}
+	}
+	return Rational{big: r}
+}
+
+func normalize(num, den int64) Rational {
+	// -MinInt64 doesn't exist, so fractions that would need it go straight to big.
+	if den == math.MinInt64 || (den < 0 && num == math.MinInt64) {
+		return fromBig(new(big.Rat).SetFrac(big.NewInt(num), big.NewInt(den)))
+	}
+	if den < 0 {
+		num, den = -num, -den
+	}
+	if g := gcd(num, den); g > 1 {
+		num, den = num/g, den/g
+	}
+	return Rational{num: num, den: den}
+}
+
+func gcd(a, b int64) int64 {
+	ua, ub := abs64(a), abs64(b)
+	for ub != 0 {
+		ua, ub = ub, ua%ub
+	}
+	return int64(ua)
+}
+
+func abs64(x int64) uint64 {
+	if x < 0 {
+		return uint64(-(x + 1)) + 1
+	}
+	return uint64(x)
+}
+
+// parts returns the int64 numerator and denominator, and false if the value lives in big.
+func (r Rational) parts() (num, den int64, ok bool) {
+	if r.big != nil {
+		return 0, 0, false
+	}
+	if r.den == 0 {
+		return 0, 1, true
+	}
+	return r.num, r.den, true
+}
+
+// Big returns the value as a new big.Rat.
+func (r Rational) Big() *big.Rat {
+	if r.big != nil {
+		return new(big.Rat).Set(r.big)
+	}
+	num, den, _ := r.parts()
+	return big.NewRat(num, den)
+}
+
+// Num and Denom return the numerator and denominator as big.Ints, which always works.
+func (r Rational) Num() *big.Int   { return r.Big().Num() }
+func (r Rational) Denom() *big.Int { return r.Big().Denom() }
+
+// Int64 returns the numerator and denominator and whether they fit in int64s.
+func (r Rational) Int64() (num, den int64, ok bool) { return r.parts() }
+
+// IsBig reports whether the value has outgrown int64s.
+func (r Rational) IsBig() bool { return r.big != nil }
+
+// Sign returns -1, 0 or +1 for a negative, zero or positive r.
+func (r Rational) Sign() int {
+	if r.big != nil {
+		return r.big.Sign()
+	}
+	switch {
+	case r.num < 0:
+		return -1
+	case r.num > 0:
+		return 1
+	}
+	return 0
+}
+
+// IsInt reports whether the denominator is 1.
+func (r Rational) IsInt() bool {
+	if r.big != nil {
+		return r.big.IsInt()
+	}
+	_, den, _ := r.parts()
+	return den == 1
+}
+
+// Neg returns -r. the one int64 fraction without a negative, MinInt64/1, moves to big.
+func (r Rational) Neg() Rational {
+	if num, den, ok := r.parts(); ok && num != math.MinInt64 {
+		return Rational{num: -num, den: den}
+	}
+	return fromBig(r.Big().Neg(r.Big()))
+}
+
+// Inv returns 1/r. it panics if r is zero.
+func (r Rational) Inv() Rational {
+	if r.Sign() == 0 {
+		panic("rational: inverse of zero")
+	}
+	if num, den, ok := r.parts(); ok {
+		return normalize(den, num)
+	}
+	b := r.Big()
+	return fromBig(b.Inv(b))
+}
+
+// Add returns r+s. Add, Sub, Mul and Quo do the arithmetic in int64 when nothing overflows and in
+// big otherwise, so the answer is always exact.
+func (r Rational) Add(s Rational) Rational {
+	if a, b, ok := r.parts(); ok {
+		if c, d, ok := s.parts(); ok {
+			// a/b + c/d = (a*d + c*b) / (b*d)
+			ad, ok1 := mul(a, d)
+			cb, ok2 := mul(c, b)
+			num, ok3 := add(ad, cb)
+			den, ok4 := mul(b, d)
+			if ok1 && ok2 && ok3 && ok4 {
+				return normalize(num, den)
+			}
+		}
+	}
+	return fromBig(new(big.Rat).Add(r.Big(), s.Big()))
+}
+
+// Sub returns r-s.
+func (r Rational) Sub(s Rational) Rational { return r.Add(s.Neg()) }
+
+// Mul returns r*s.
+func (r Rational) Mul(s Rational) Rational {
+	if a, b, ok := r.parts(); ok {
+		if c, d, ok := s.parts(); ok {
+			// cross-cancel first so the products stay small.
+			g1, g2 := gcd(a, d), gcd(c, b)
+			num, ok1 := mul(a/g1, c/g2)
+			den, ok2 := mul(b/g2, d/g1)
+			if ok1 && ok2 {
+				return normalize(num, den)
+			}
+		}
+	}
+	return fromBig(new(big.Rat).Mul(r.Big(), s.Big()))
+}
+
+// Quo returns r/s. it panics if s is zero.
+func (r Rational) Quo(s Rational) Rational { return r.Mul(s.Inv()) }
+
+// Cmp returns -1, 0 or +1 depending on whether r is less than, equal to or greater than s.
+func (r Rational) Cmp(s Rational) int {
+	if a, b, ok := r.parts(); ok {
+		if c, d, ok := s.parts(); ok {
+			ad, ok1 := mul(a, d)
+			cb, ok2 := mul(c, b)
+			if ok1 && ok2 {
+				switch {
+				case ad < cb:
+					return -1
+				case ad > cb:
+					return 1
+				}
+				return 0
+			}
+		}
+	}
+	return r.Big().Cmp(s.Big())
+}
+
+// Equal reports whether r and s are the same number, however each is stored.
+func (r Rational) Equal(s Rational) bool { return r.Cmp(s) == 0 }
+
+// String writes r as "num/den", or just "num" when it's a whole number, the same as
+// big.Rat.RatString.
+func (r Rational) String() string {
+	if r.big != nil {
+		return r.big.RatString()
+	}
+	num, den, _ := r.parts()
+	if den == 1 {
+		return fmt.Sprint(num)
+	}
+	return fmt.Sprintf("%d/%d", num, den)
+}
+
+func mul(a, b int64) (int64, bool) {
+	hi, lo := bits.Mul64(abs64(a), abs64(b))
+	neg := (a < 0) != (b < 0)
+	if hi != 0 || lo > math.MaxInt64+1 || (lo == math.MaxInt64+1 && !neg) {
+		return 0, false
+	}
+	if neg {
+		return int64(-lo), true
+	}
+	return int64(lo), true
+}
+
+func add(a, b int64) (int64, bool) {
+	s := a + b
+	if (a >= 0) == (b >= 0) && (s >= 0) != (a >= 0) {
+		return 0, false
+	}
+	return s, true
+}
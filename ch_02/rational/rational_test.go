@@ -0,0 +1,292 @@
+package rational
+
+import (
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	for _, c := range []struct {
+		num, den int64
+		want     string
+	}{
+		{10, 3, "10/3"},
+		{4, 8, "1/2"},
+		{3, -6, "-1/2"},
+		{-3, -6, "1/2"},
+		{0, 5, "0"},
+		{10, 5, "2"},
+		{math.MinInt64, 1, "-9223372036854775808"},
+		{math.MinInt64, -1, "9223372036854775808"},
+		{1, math.MinInt64, "-1/9223372036854775808"},
+	} {
+		if got := New(c.num, c.den).String(); got != c.want {
+			t.Errorf("New(%d, %d) = %s, want %s", c.num, c.den, got, c.want)
+		}
+	}
+	var zero Rational
+	if zero.String() != "0" || zero.Sign() != 0 || !zero.Equal(FromInt(0)) || !zero.IsInt() {
+		t.Errorf("zero value is %s", zero)
+	}
+}
+
+// TestOverflow pushes each operation past int64 and checks the answer is still exact, and that it
+// comes back to int64s once it fits again.
+func TestOverflow(t *testing.T) {
+	hi, lo := FromInt(math.MaxInt64), FromInt(math.MinInt64)
+	for _, c := range []struct {
+		name string
+		got  Rational
+		want string
+	}{
+		{"MaxInt64 + 1", hi.Add(FromInt(1)), "9223372036854775808"},
+		{"MinInt64 - 1", lo.Sub(FromInt(1)), "-9223372036854775809"},
+		{"MaxInt64 * 2", hi.Mul(FromInt(2)), "18446744073709551614"},
+		{"MinInt64 * -1", lo.Mul(FromInt(-1)), "9223372036854775808"},
+		{"-MinInt64", lo.Neg(), "9223372036854775808"},
+		{"1/MaxInt64 / MaxInt64", New(1, math.MaxInt64).Quo(hi), "1/85070591730234615847396907784232501249"},
+		{"1/MaxInt64 + 1/(MaxInt64-1)", New(1, math.MaxInt64).Add(New(1, math.MaxInt64-1)), "18446744073709551613/85070591730234615838173535747377725442"},
+	} {
+		if c.got.String() != c.want || !c.got.IsBig() {
+			t.Errorf("%s = %s (big %v), want %s in big", c.name, c.got, c.got.IsBig(), c.want)
+		}
+	}
+
+	back := hi.Add(FromInt(1)).Sub(FromInt(2))
+	if back.IsBig() || !back.Equal(FromInt(math.MaxInt64-1)) {
+		t.Errorf("(MaxInt64+1)-2 = %s, big %v: should be back in int64s", back, back.IsBig())
+	}
+	if num, den, ok := back.Int64(); !ok || num != math.MaxInt64-1 || den != 1 {
+		t.Errorf("Int64() = %d, %d, %v", num, den, ok)
+	}
+	if _, _, ok := hi.Add(hi).Int64(); ok {
+		t.Error("Int64() of a big value reported ok")
+	}
+}
+
+// TestAgainstBig runs every operation on operands near the edges of int64 and checks them against
+// big.Rat.
+func TestAgainstBig(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	operand := func() Rational {
+		num := rng.Int64() >> rng.IntN(64)
+		if rng.IntN(2) == 0 {
+			num = -num
+		}
+		return New(num, rng.Int64()>>rng.IntN(63)|1)
+	}
+	for range 20_000 {
+		a, b := operand(), operand()
+		check := func(op string, got Rational, want *big.Rat) {
+			if got.Big().Cmp(want) != 0 {
+				t.Fatalf("%s %s %s = %s, want %s", a, op, b, got, want.RatString())
+			}
+			if got.IsBig() && want.Num().IsInt64() && want.Denom().IsInt64() {
+				t.Fatalf("%s %s %s = %s is in big but fits in int64s", a, op, b, got)
+			}
+		}
+		check("+", a.Add(b), new(big.Rat).Add(a.Big(), b.Big()))
+		check("-", a.Sub(b), new(big.Rat).Sub(a.Big(), b.Big()))
+		check("*", a.Mul(b), new(big.Rat).Mul(a.Big(), b.Big()))
+		if b.Sign() != 0 {
+			check("/", a.Quo(b), new(big.Rat).Quo(a.Big(), b.Big()))
+		}
+		if got, want := a.Cmp(b), a.Big().Cmp(b.Big()); got != want {
+			t.Fatalf("Cmp(%s, %s) = %d, want %d", a, b, got, want)
+		}
+	}
+}
+
+func TestPanics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"New(1, 0)":      func() { New(1, 0) },
+		"Inv of zero":    func() { FromInt(0).Inv() },
+		"Quo by zero":    func() { FromInt(1).Quo(Rational{}) },
+		"Approximate(0)": func() { New(1, 3).Approximate(0) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s didn't panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, c := range []struct{ in, want string }{
+		{"10/3", "10/3"},
+		{"-7/2", "-7/2"},
+		{" 4/8 ", "1/2"},
+		{"0.1", "1/10"},
+		{"-1.25", "-5/4"},
+		{"1e-3", "1/1000"},
+		{"2.5e3", "2500"},
+		{"42", "42"},
+		{"123456789012345678901234567890", "123456789012345678901234567890"},
+	} {
+		r, err := Parse(c.in)
+		if err != nil || r.String() != c.want {
+			t.Errorf("Parse(%q) = %s, %v, want %s", c.in, r, err, c.want)
+		}
+	}
+	for _, in := range []string{"", "abc", "1/0", "1/2/3", "1.2.3", "1/-2", "--1", "0.1x"} {
+		if r, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %s, want an error", in, r)
+		}
+	}
+
+	var r Rational
+	if err := r.UnmarshalText([]byte("6/4")); err != nil || r.String() != "3/2" {
+		t.Errorf("UnmarshalText = %s, %v", r, err)
+	}
+	if text, _ := r.MarshalText(); string(text) != "3/2" {
+		t.Errorf("MarshalText = %s", text)
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	for _, c := range []struct {
+		r         Rational
+		maxDigits int
+		want      string
+		exact     bool
+	}{
+		{New(1, 8), 10, "0.125", true},
+		{New(-1, 4), 10, "-0.25", true},
+		{FromInt(5), 3, "5", true},
+		{New(1, 3), 5, "0.33333", false},
+		{New(2, 3), 3, "0.667", false},
+		{New(1, 1024), 10, "0.0009765625", true},
+		{New(1, 1024), 5, "0.00098", false},
+		{New(7, 20), 2, "0.35", true},
+	} {
+		got, exact := c.r.Decimal(c.maxDigits)
+		if got != c.want || exact != c.exact {
+			t.Errorf("%s.Decimal(%d) = %q, %v, want %q, %v", c.r, c.maxDigits, got, exact, c.want, c.exact)
+		}
+	}
+}
+
+func TestRepeatingDecimal(t *testing.T) {
+	for _, c := range []struct {
+		r    Rational
+		want string
+		ok   bool
+	}{
+		{New(1, 3), "0.(3)", true},
+		{New(1, 6), "0.1(6)", true},
+		{New(1, 7), "0.(142857)", true},
+		{New(-22, 7), "-3.(142857)", true},
+		{New(1, 4), "0.25", true},
+		{New(5, 1), "5", true},
+		{New(1, 12), "0.08(3)", true},
+		{New(1, 97), "0.0103092783", false}, // the repetend is 96 digits
+	} {
+		got, ok := c.r.RepeatingDecimal(10)
+		if got != c.want || ok != c.ok {
+			t.Errorf("%s.RepeatingDecimal(10) = %q, %v, want %q, %v", c.r, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestFloat64(t *testing.T) {
+	tenth, _ := Parse("0.1")
+	fromTenth, _ := FromFloat64(0.1)
+	for _, c := range []struct {
+		r     Rational
+		want  float64
+		exact bool
+	}{
+		{New(3, 4), 0.75, true},
+		{New(1, 3), 1.0 / 3, false},
+		{tenth, 0.1, false},
+		{fromTenth, 0.1, true},
+		{FromInt(1 << 53), 1 << 53, true},
+		{FromInt(1<<53 + 1), 1 << 53, false},
+		{New(1, 1<<62), 0x1p-62, true},
+		{FromInt(math.MaxInt64).Add(FromInt(1)), 0x1p63, true},
+	} {
+		got, exact := c.r.Float64()
+		if got != c.want || exact != c.exact {
+			t.Errorf("%s.Float64() = %v, %v, want %v, %v", c.r, got, exact, c.want, c.exact)
+		}
+	}
+	if fromTenth.String() != "3602879701896397/36028797018963968" {
+		t.Errorf("FromFloat64(0.1) = %s", fromTenth)
+	}
+	for _, f := range []float64{math.Inf(1), math.NaN()} {
+		if _, err := FromFloat64(f); err == nil {
+			t.Errorf("FromFloat64(%v) succeeded", f)
+		}
+	}
+}
+
+func TestContinuedFraction(t *testing.T) {
+	ints := func(ns ...int64) []*big.Int {
+		out := make([]*big.Int, len(ns))
+		for i, n := range ns {
+			out[i] = big.NewInt(n)
+		}
+		return out
+	}
+	eq := func(a, b *big.Int) bool { return a.Cmp(b) == 0 }
+	for _, c := range []struct {
+		r     Rational
+		terms []*big.Int
+	}{
+		{New(415, 93), ints(4, 2, 6, 7)},
+		{New(-415, 93), ints(-5, 1, 1, 6, 7)},
+		{New(355, 113), ints(3, 7, 16)},
+		{FromInt(7), ints(7)},
+	} {
+		got := c.r.ContinuedFraction()
+		if !slices.EqualFunc(got, c.terms, eq) {
+			t.Errorf("%s.ContinuedFraction() = %v, want %v", c.r, got, c.terms)
+		}
+		if back := FromContinuedFraction(got); !back.Equal(c.r) {
+			t.Errorf("FromContinuedFraction(%v) = %s, want %s", got, back, c.r)
+		}
+	}
+
+	pi, _ := FromFloat64(math.Pi)
+	var got []string
+	for _, c := range pi.Convergents()[:5] {
+		got = append(got, c.String())
+	}
+	if want := []string{"3", "22/7", "333/106", "355/113", "103993/33102"}; !slices.Equal(got, want) {
+		t.Errorf("convergents of π = %v, want %v", got, want)
+	}
+	if all := pi.Convergents(); !all[len(all)-1].Equal(pi) {
+		t.Errorf("last convergent of π is %s, not π", all[len(all)-1])
+	}
+}
+
+func TestApproximate(t *testing.T) {
+	for _, c := range []struct {
+		f      float64
+		maxDen int64
+		want   string
+	}{
+		{math.Pi, 1000, "355/113"},
+		{math.Pi, 10, "22/7"},
+		{math.Pi, 100, "311/99"}, // a semiconvergent, closer than 22/7
+		{math.Pi, 1, "3"},
+		{0.1, 1000, "1/10"},
+		{-0.75, 100, "-3/4"},
+		{math.E, 1000, "1457/536"},
+	} {
+		r, err := ApproximateFloat(c.f, c.maxDen)
+		if err != nil || r.String() != c.want {
+			t.Errorf("ApproximateFloat(%v, %d) = %s, %v, want %s", c.f, c.maxDen, r, err, c.want)
+		}
+	}
+	if r := New(2, 3).Approximate(5); r.String() != "2/3" {
+		t.Errorf("Approximate of something already small enough = %s", r)
+	}
+}
@@ -0,0 +1,60 @@
+package zerovalue
+
+import "reflect"
+
+// IsZero reports whether v is its type's zero value. it agrees with reflect.Value.IsZero, so a
+// nil slice is zero and an empty one isn't. a nil interface is zero.
+func IsZero(v any) bool {
+	if v == nil {
+		return true
+	}
+	return reflect.ValueOf(v).IsZero()
+}
+
+// IsEmpty is the looser, recursive check: a value is empty if it is zero, or if it's a slice or
+// map with no elements, an array or struct whose every element is empty, or a pointer or
+// interface holding an empty value. so []int{}, map[string]int{} and &person{} are all empty,
+// though none of them is zero.
+func IsEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+	return isEmpty(reflect.ValueOf(v), map[uintptr]bool{})
+}
+
+func isEmpty(v reflect.Value, visiting map[uintptr]bool) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Array:
+		for i := range v.Len() {
+			if !isEmpty(v.Index(i), visiting) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if !isEmpty(v.Field(i), visiting) {
+				return false
+			}
+		}
+		return true
+	case reflect.Pointer:
+		if v.IsNil() {
+			return true
+		}
+		// a pointer cycle is only reached through pointers, so it can't be empty all the way
+		// round; treating the revisit as empty lets the rest of the value decide.
+		p := v.Pointer()
+		if visiting[p] {
+			return true
+		}
+		visiting[p] = true
+		defer delete(visiting, p)
+		return isEmpty(v.Elem(), visiting)
+	case reflect.Interface:
+		return v.IsNil() || isEmpty(v.Elem(), visiting)
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,204 @@
+package zerovalue
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Named types ParseType knows by name: the builtins, the ones the chapters declare, and a few
+// from the standard library whose zero values are famously usable.
+var Named = map[string]reflect.Type{
+	"bool":       reflect.TypeFor[bool](),
+	"string":     reflect.TypeFor[string](),
+	"int":        reflect.TypeFor[int](),
+	"int8":       reflect.TypeFor[int8](),
+	"int16":      reflect.TypeFor[int16](),
+	"int32":      reflect.TypeFor[int32](),
+	"int64":      reflect.TypeFor[int64](),
+	"uint":       reflect.TypeFor[uint](),
+	"uint8":      reflect.TypeFor[uint8](),
+	"uint16":     reflect.TypeFor[uint16](),
+	"uint32":     reflect.TypeFor[uint32](),
+	"uint64":     reflect.TypeFor[uint64](),
+	"uintptr":    reflect.TypeFor[uintptr](),
+	"byte":       reflect.TypeFor[byte](),
+	"rune":       reflect.TypeFor[rune](),
+	"float32":    reflect.TypeFor[float32](),
+	"float64":    reflect.TypeFor[float64](),
+	"complex64":  reflect.TypeFor[complex64](),
+	"complex128": reflect.TypeFor[complex128](),
+	"any":        reflect.TypeFor[any](),
+	"error":      reflect.TypeFor[error](),
+
+	// structs() and exerciseNo3() in ch_03.
+	"person": reflect.TypeFor[struct {
+		name string
+		age  int
+		pet  string
+	}](),
+	"Employee": reflect.TypeFor[struct {
+		firstName string
+		lastName  string
+		id        int
+	}](),
+
+	"time.Time":       reflect.TypeFor[time.Time](),
+	"time.Duration":   reflect.TypeFor[time.Duration](),
+	"sync.Mutex":      reflect.TypeFor[sync.Mutex](),
+	"sync.WaitGroup":  reflect.TypeFor[sync.WaitGroup](),
+	"bytes.Buffer":    reflect.TypeFor[bytes.Buffer](),
+	"strings.Builder": reflect.TypeFor[strings.Builder](),
+}
+
+// ParseType turns a Go type expression like "map[string][]*person" or
+// "struct{ name string; tags []string }" into a reflect.Type. interfaces other than any and error
+// can't be built at run time, so they're an error.
+func ParseType(expr string) (reflect.Type, error) {
+	e, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	return typeOf(e)
+}
+
+func typeOf(e ast.Expr) (reflect.Type, error) {
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return typeOf(e.X)
+
+	case *ast.Ident:
+		if t, ok := Named[e.Name]; ok {
+			return t, nil
+		}
+		return nil, fmt.Errorf("unknown type %s", e.Name)
+
+	case *ast.SelectorExpr:
+		name := fmt.Sprintf("%s.%s", e.X, e.Sel.Name)
+		if t, ok := Named[name]; ok {
+			return t, nil
+		}
+		return nil, fmt.Errorf("unknown type %s", name)
+
+	case *ast.StarExpr:
+		elem, err := typeOf(e.X)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.PointerTo(elem), nil
+
+	case *ast.ArrayType:
+		elem, err := typeOf(e.Elt)
+		if err != nil {
+			return nil, err
+		}
+		if e.Len == nil {
+			return reflect.SliceOf(elem), nil
+		}
+		lit, ok := e.Len.(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT {
+			return nil, fmt.Errorf("array length must be an integer literal")
+		}
+		n, err := strconv.ParseInt(lit.Value, 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ArrayOf(int(n), elem), nil
+
+	case *ast.MapType:
+		key, err := typeOf(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		if !key.Comparable() {
+			return nil, fmt.Errorf("invalid map key type %s", key)
+		}
+		val, err := typeOf(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.MapOf(key, val), nil
+
+	case *ast.ChanType:
+		elem, err := typeOf(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		dir := reflect.BothDir
+		switch e.Dir {
+		case ast.SEND:
+			dir = reflect.SendDir
+		case ast.RECV:
+			dir = reflect.RecvDir
+		}
+		return reflect.ChanOf(dir, elem), nil
+
+	case *ast.StructType:
+		var fields []reflect.StructField
+		for _, f := range e.Fields.List {
+			ft, err := typeOf(f.Type)
+			if err != nil {
+				return nil, err
+			}
+			names := f.Names
+			if len(names) == 0 {
+				return nil, fmt.Errorf("embedded fields aren't supported")
+			}
+			for _, n := range names {
+				sf := reflect.StructField{Name: n.Name, Type: ft}
+				if !n.IsExported() {
+					// reflect needs a package for unexported names.
+					sf.PkgPath = "main"
+				}
+				fields = append(fields, sf)
+			}
+		}
+		return reflect.StructOf(fields), nil
+
+	case *ast.FuncType:
+		in, variadic, err := fieldTypes(e.Params)
+		if err != nil {
+			return nil, err
+		}
+		out, _, err := fieldTypes(e.Results)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.FuncOf(in, out, variadic), nil
+
+	case *ast.InterfaceType:
+		if len(e.Methods.List) == 0 {
+			return reflect.TypeFor[any](), nil
+		}
+		return nil, fmt.Errorf("interfaces with methods can't be built at run time, use any or error")
+	}
+	return nil, fmt.Errorf("unsupported type expression %T", e)
+}
+
+func fieldTypes(fl *ast.FieldList) (types []reflect.Type, variadic bool, err error) {
+	if fl == nil {
+		return nil, false, nil
+	}
+	for _, f := range fl.List {
+		expr := f.Type
+		if ell, ok := expr.(*ast.Ellipsis); ok {
+			variadic = true
+			expr = &ast.ArrayType{Elt: ell.Elt}
+		}
+		t, err := typeOf(expr)
+		if err != nil {
+			return nil, false, err
+		}
+		for range max(len(f.Names), 1) {
+			types = append(types, t)
+		}
+	}
+	return types, variadic, nil
+}
@@ -0,0 +1,184 @@
+// Package zerovalue explains the zero value of any Go type.
+//
+// ch_02 opens with "there's a 'zero value' for every type", and ch_03 finds out the hard way that
+// the zero value isn't always usable: a nil slice can be appended to but a nil map can't be
+// written. Describe walks a type and says, for every part of it, what its zero value is and what
+// you can and can't do with it.
+package zerovalue
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Node describes the zero value of one type, and of the types inside it.
+type Node struct {
+	Name     string // field name or element role ("elem", "key"); empty at the root
+	Type     reflect.Type
+	Zero     string   // the zero value as it would be written in Go, or as %v prints it
+	Usable   bool     // whether the zero value works without initialising it first
+	Notes    []string // what works and what doesn't
+	Children []Node
+}
+
+// Describe walks t. recursive types (a struct holding a pointer to itself) are only expanded once.
+func Describe(t reflect.Type) Node {
+	return describe("", t, map[reflect.Type]bool{})
+}
+
+func describe(name string, t reflect.Type, seen map[reflect.Type]bool) Node {
+	n := Node{Name: name, Type: t, Usable: true}
+	switch t.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Pointer, reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		// %v prints a nil map as map[] and a nil slice as [], which hides the nil.
+		n.Zero = "nil"
+	case reflect.String:
+		n.Zero = `""`
+	default:
+		n.Zero = fmt.Sprintf("%v", reflect.Zero(t))
+	}
+
+	if seen[t] {
+		n.Notes = append(n.Notes, "recursive, described above")
+		return n
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	if isKnown(t) {
+		// a nil pointer inside time.Time or strings.Builder is an implementation detail; their
+		// packages promise the zero value works, which is what matters.
+		n.Notes = append(n.Notes, "ready to use, its package documents the zero value as usable")
+		return n
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+		n.Notes = append(n.Notes, "ready to use")
+
+	case reflect.Slice:
+		n.Notes = append(n.Notes,
+			"nil: len and cap are 0, range does nothing, append allocates",
+			"indexing a nil slice panics (there is no element 0)",
+			"== nil is true, unlike an empty slice from []T{} or make",
+		)
+		n.Children = append(n.Children, describe("elem", t.Elem(), seen))
+
+	case reflect.Map:
+		n.Usable = false
+		n.Notes = append(n.Notes,
+			"nil: reads return the value type's zero value, len is 0, range and delete do nothing",
+			"writing to a nil map panics, make it or use a literal first",
+		)
+		n.Children = append(n.Children, describe("key", t.Key(), seen), describe("value", t.Elem(), seen))
+
+	case reflect.Pointer:
+		n.Usable = false
+		n.Notes = append(n.Notes, "nil: comparing with nil works, dereferencing panics")
+		n.Children = append(n.Children, describe("elem", t.Elem(), seen))
+
+	case reflect.Chan:
+		n.Usable = false
+		n.Notes = append(n.Notes,
+			"nil: sends and receives block forever, close panics",
+			"a nil channel in a select is never chosen, which can be handy to switch a case off",
+		)
+		n.Children = append(n.Children, describe("elem", t.Elem(), seen))
+
+	case reflect.Func:
+		n.Usable = false
+		n.Notes = append(n.Notes, "nil: calling it panics")
+
+	case reflect.Interface:
+		n.Usable = false
+		if t.NumMethod() == 0 {
+			n.Notes = append(n.Notes, "nil: holds no value, type assertions fail (or panic without comma-ok)")
+		} else {
+			n.Notes = append(n.Notes, "nil: calling a method panics")
+		}
+
+	case reflect.Array:
+		if t.Len() == 0 {
+			n.Notes = append(n.Notes, "zero length, takes no memory")
+			break
+		}
+		elem := describe("elem", t.Elem(), seen)
+		n.Usable = !blocks(elem)
+		n.Notes = append(n.Notes, fmt.Sprintf("%d elements, each the element type's zero value", t.Len()))
+		n.Children = append(n.Children, elem)
+
+	case reflect.Struct:
+		for i := range t.NumField() {
+			f := t.Field(i)
+			child := describe(f.Name, f.Type, seen)
+			if blocks(child) {
+				n.Usable = false
+			}
+			n.Children = append(n.Children, child)
+		}
+		if n.Usable {
+			n.Notes = append(n.Notes, "no field needs making before use, so the struct is usable")
+		} else {
+			n.Notes = append(n.Notes, "some fields need initialising before use: a map to write to or a channel to use")
+		}
+
+	case reflect.UnsafePointer:
+		n.Usable = false
+		n.Notes = append(n.Notes, "nil")
+	}
+	return n
+}
+
+// isKnown reports whether t is one of the named standard library types in Named.
+func isKnown(t reflect.Type) bool {
+	if t.Name() == "" || t.PkgPath() == "" {
+		return false
+	}
+	for _, k := range Named {
+		if k == t {
+			return true
+		}
+	}
+	return false
+}
+
+// blocks reports whether an unusable part makes the struct or array holding it unusable too. a nil
+// pointer, func or interface field is only a problem if it's used before being set, which is true
+// of any field; a nil map can't be written and a nil channel blocks forever, so those have to be
+// made first, and so does whatever holds them.
+func blocks(n Node) bool {
+	switch n.Type.Kind() {
+	case reflect.Map, reflect.Chan, reflect.Struct, reflect.Array:
+		return !n.Usable
+	}
+	return false
+}
+
+// String renders the description as an indented tree.
+func (n Node) String() string {
+	var b strings.Builder
+	n.write(&b, 0)
+	return b.String()
+}
+
+func (n Node) write(b *strings.Builder, depth int) {
+	indent := strings.Repeat("  ", depth)
+	label := n.Type.String()
+	if n.Name != "" {
+		label = n.Name + " " + label
+	}
+	usable := "usable"
+	if !n.Usable {
+		usable = "needs initialising"
+	}
+	fmt.Fprintf(b, "%s%s: zero value %s (%s)\n", indent, label, n.Zero, usable)
+	for _, note := range n.Notes {
+		fmt.Fprintf(b, "%s  - %s\n", indent, note)
+	}
+	for _, c := range n.Children {
+		c.write(b, depth+1)
+	}
+}
@@ -0,0 +1,127 @@
+package zerovalue
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type list struct {
+	val  int
+	next *list
+}
+
+func TestUsable(t *testing.T) {
+	for _, c := range []struct {
+		t    reflect.Type
+		want bool
+	}{
+		{reflect.TypeFor[int](), true},
+		{reflect.TypeFor[[]int](), true},
+		{reflect.TypeFor[map[string]int](), false},
+		{reflect.TypeFor[chan int](), false},
+		{reflect.TypeFor[*int](), false},
+		{reflect.TypeFor[func()](), false},
+		{reflect.TypeFor[error](), false},
+
+		// the standard library types in Named, whose internals hold nil pointers.
+		{reflect.TypeFor[strings.Builder](), true},
+		{reflect.TypeFor[time.Time](), true},
+		{reflect.TypeFor[bytes.Buffer](), true},
+		{reflect.TypeFor[sync.Mutex](), true},
+		{reflect.TypeFor[sync.WaitGroup](), true},
+
+		// a nil pointer, func or interface field doesn't stop the struct being used.
+		{reflect.TypeFor[list](), true},
+		{reflect.TypeFor[struct {
+			b    strings.Builder
+			when time.Time
+			err  error
+			f    func()
+		}](), true},
+		{reflect.TypeFor[struct{ m map[string]int }](), false},
+		{reflect.TypeFor[struct{ c chan int }](), false},
+		{reflect.TypeFor[struct{ inner struct{ m map[int]int } }](), false},
+		{reflect.TypeFor[[3]struct{ m map[int]int }](), false},
+		{reflect.TypeFor[[3]*int](), true},
+	} {
+		if got := Describe(c.t).Usable; got != c.want {
+			t.Errorf("Describe(%v).Usable = %v, want %v", c.t, got, c.want)
+		}
+	}
+}
+
+func TestKnownTypesNotExpanded(t *testing.T) {
+	n := Describe(reflect.TypeFor[time.Time]())
+	if len(n.Children) != 0 {
+		t.Errorf("time.Time described %d fields, want none", len(n.Children))
+	}
+}
+
+func TestRecursive(t *testing.T) {
+	n := Describe(reflect.TypeFor[list]())
+	next := n.Children[1].Children[0]
+	if next.Type != reflect.TypeFor[list]() || len(next.Children) != 0 {
+		t.Errorf("recursive list expanded again:\n%s", n)
+	}
+}
+
+func TestParseType(t *testing.T) {
+	for expr, want := range map[string]reflect.Type{
+		"map[string][]*int":         reflect.TypeFor[map[string][]*int](),
+		"[4]byte":                   reflect.TypeFor[[4]byte](),
+		"chan<- error":              reflect.TypeFor[chan<- error](),
+		"func(int, ...string) bool": reflect.TypeFor[func(int, ...string) bool](),
+		"strings.Builder":           reflect.TypeFor[strings.Builder](),
+	} {
+		got, err := ParseType(expr)
+		if err != nil || got != want {
+			t.Errorf("ParseType(%q) = %v, %v, want %v", expr, got, err, want)
+		}
+	}
+	for _, expr := range []string{"nope", "map[[]int]int", "interface{ M() }", "[n]int"} {
+		if _, err := ParseType(expr); err == nil {
+			t.Errorf("ParseType(%q) succeeded", expr)
+		}
+	}
+
+	st, err := ParseType("struct{ b strings.Builder; next *person }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Describe(st).Usable {
+		t.Errorf("%v should be usable", st)
+	}
+}
+
+func TestIsZeroAndEmpty(t *testing.T) {
+	for _, c := range []struct {
+		v           any
+		zero, empty bool
+	}{
+		{nil, true, true},
+		{0, true, true},
+		{[]int(nil), true, true},
+		{[]int{}, false, true},
+		{map[string]int{}, false, true},
+		{&list{}, false, true},
+		{list{val: 1}, false, false},
+		{[2]int{0, 1}, false, false},
+	} {
+		if got := IsZero(c.v); got != c.zero {
+			t.Errorf("IsZero(%#v) = %v, want %v", c.v, got, c.zero)
+		}
+		if got := IsEmpty(c.v); got != c.empty {
+			t.Errorf("IsEmpty(%#v) = %v, want %v", c.v, got, c.empty)
+		}
+	}
+
+	cyc := &list{}
+	cyc.next = cyc
+	if !IsEmpty(cyc) {
+		t.Error("IsEmpty on a cycle of zero lists = false")
+	}
+}
@@ -0,0 +1,232 @@
+// Package bitset is a set of small non-negative ints, one bit each.
+//
+// mapsInGo() dedups vals with intSet := map[int]bool{}, which spends a whole hash entry (key,
+// value, hash bits, overflow pointers) on each number from 1 to 10. a BitSet spends one bit: the
+// same set fits in a single uint64, and union or intersection handle 64 numbers per instruction.
+// it's the right tool when the values are small and dense; for a few huge numbers a map still wins.
+package bitset
+
+import (
+	"iter"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// BitSet is a set of non-negative ints. the zero value is empty and ready to use. it isn't safe
+// for concurrent use.
+type BitSet struct {
+	words []uint64 // bit i%64 of words[i/64] is set when i is in the set
+}
+
+// FromSlice returns a set holding the values in s.
+func FromSlice(s []int) *BitSet {
+	b := &BitSet{}
+	for _, v := range s {
+		b.Add(v)
+	}
+	return b
+}
+
+func index(i int) (word int, mask uint64) {
+	if i < 0 {
+		panic("bitset: negative value " + strconv.Itoa(i))
+	}
+	return i / 64, 1 << (uint(i) % 64)
+}
+
+// Add puts i in the set, growing it if needed. it panics if i is negative.
+func (b *BitSet) Add(i int) {
+	w, mask := index(i)
+	if w >= len(b.words) {
+		b.words = append(b.words, make([]uint64, w+1-len(b.words))...)
+	}
+	b.words[w] |= mask
+}
+
+// Remove takes i out of the set.
+func (b *BitSet) Remove(i int) {
+	w, mask := index(i)
+	if w < len(b.words) {
+		b.words[w] &^= mask
+	}
+}
+
+// Contains reports whether i is in the set. negative values never are.
+func (b *BitSet) Contains(i int) bool {
+	if i < 0 {
+		return false
+	}
+	w, mask := index(i)
+	return w < len(b.words) && b.words[w]&mask != 0
+}
+
+// Len is the number of values in the set.
+func (b *BitSet) Len() int {
+	n := 0
+	for _, w := range b.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Clear empties the set, keeping its memory.
+func (b *BitSet) Clear() { clear(b.words) }
+
+// Clone returns an independent copy.
+func (b *BitSet) Clone() *BitSet {
+	return &BitSet{words: append([]uint64(nil), b.words...)}
+}
+
+// UnionWith adds every value in o to b.
+func (b *BitSet) UnionWith(o *BitSet) {
+	if len(o.words) > len(b.words) {
+		b.words = append(b.words, make([]uint64, len(o.words)-len(b.words))...)
+	}
+	for i, w := range o.words {
+		b.words[i] |= w
+	}
+}
+
+// IntersectWith keeps only the values that are also in o.
+func (b *BitSet) IntersectWith(o *BitSet) {
+	for i := range b.words {
+		if i < len(o.words) {
+			b.words[i] &= o.words[i]
+		} else {
+			b.words[i] = 0
+		}
+	}
+	b.trim()
+}
+
+// DifferenceWith removes every value that's in o.
+func (b *BitSet) DifferenceWith(o *BitSet) {
+	for i := range min(len(b.words), len(o.words)) {
+		b.words[i] &^= o.words[i]
+	}
+	b.trim()
+}
+
+// SymmetricDifferenceWith keeps the values in exactly one of b and o.
+func (b *BitSet) SymmetricDifferenceWith(o *BitSet) {
+	if len(o.words) > len(b.words) {
+		b.words = append(b.words, make([]uint64, len(o.words)-len(b.words))...)
+	}
+	for i, w := range o.words {
+		b.words[i] ^= w
+	}
+	b.trim()
+}
+
+// trim drops zero words from the end, so a set that shrank doesn't keep scanning empty words.
+func (b *BitSet) trim() {
+	n := len(b.words)
+	for n > 0 && b.words[n-1] == 0 {
+		n--
+	}
+	b.words = b.words[:n]
+}
+
+// Union returns a new set with the values in either a or b. Intersection and Difference are the
+// same for their operations.
+func Union(a, b *BitSet) *BitSet {
+	out := a.Clone()
+	out.UnionWith(b)
+	return out
+}
+
+func Intersection(a, b *BitSet) *BitSet {
+	out := a.Clone()
+	out.IntersectWith(b)
+	return out
+}
+
+func Difference(a, b *BitSet) *BitSet {
+	out := a.Clone()
+	out.DifferenceWith(b)
+	return out
+}
+
+// Equal reports whether a and b hold the same values.
+func Equal(a, b *BitSet) bool {
+	long, short := a.words, b.words
+	if len(short) > len(long) {
+		long, short = short, long
+	}
+	for i, w := range long {
+		if i < len(short) {
+			if w != short[i] {
+				return false
+			}
+		} else if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Rank is how many values in the set are less than i.
+func (b *BitSet) Rank(i int) int {
+	if i <= 0 {
+		return 0
+	}
+	w, mask := index(i)
+	n := 0
+	for _, word := range b.words[:min(w, len(b.words))] {
+		n += bits.OnesCount64(word)
+	}
+	if w < len(b.words) {
+		n += bits.OnesCount64(b.words[w] & (mask - 1))
+	}
+	return n
+}
+
+// Select returns the value with rank k, the k-th smallest counting from 0. ok is false when the
+// set has k or fewer values.
+func (b *BitSet) Select(k int) (v int, ok bool) {
+	if k < 0 {
+		return 0, false
+	}
+	for i, w := range b.words {
+		c := bits.OnesCount64(w)
+		if k >= c {
+			k -= c
+			continue
+		}
+		// clear the lowest set bit k times, then the next one is it.
+		for range k {
+			w &= w - 1
+		}
+		return i*64 + bits.TrailingZeros64(w), true
+	}
+	return 0, false
+}
+
+// All iterates over the values in increasing order.
+func (b *BitSet) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i, w := range b.words {
+			for w != 0 {
+				if !yield(i*64 + bits.TrailingZeros64(w)) {
+					return
+				}
+				w &= w - 1
+			}
+		}
+	}
+}
+
+// String writes the set like fmt writes a slice, {1 2 3}.
+func (b *BitSet) String() string {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for v := range b.All() {
+		if sb.Len() > 1 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(strconv.Itoa(v))
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
@@ -0,0 +1,179 @@
+package bitset
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// random returns a set of about n values below limit, and the same values in the map[int]bool
+// form mapsInGo() uses for intSet.
+func random(seed uint64, n, limit int) (*BitSet, map[int]bool) {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	b, m := &BitSet{}, map[int]bool{}
+	for range n {
+		v := rng.IntN(limit)
+		b.Add(v)
+		m[v] = true
+	}
+	return b, m
+}
+
+func TestAgainstMap(t *testing.T) {
+	a, am := random(1, 500, 2000)
+	b, bm := random(2, 500, 3000)
+
+	check := func(name string, got *BitSet, keep func(v int) bool) {
+		t.Helper()
+		var want []int
+		for v := range 3000 {
+			if keep(v) {
+				want = append(want, v)
+			}
+		}
+		if g := slices.Collect(got.All()); !slices.Equal(g, want) {
+			t.Errorf("%s = %v, want %v", name, g, want)
+		}
+		if got.Len() != len(want) {
+			t.Errorf("%s Len = %d, want %d", name, got.Len(), len(want))
+		}
+	}
+	check("Union", Union(a, b), func(v int) bool { return am[v] || bm[v] })
+	check("Intersection", Intersection(a, b), func(v int) bool { return am[v] && bm[v] })
+	check("Difference", Difference(a, b), func(v int) bool { return am[v] && !bm[v] })
+	sym := a.Clone()
+	sym.SymmetricDifferenceWith(b)
+	check("SymmetricDifference", sym, func(v int) bool { return am[v] != bm[v] })
+
+	vals := slices.Collect(a.All())
+	for k, v := range vals {
+		if r := a.Rank(v); r != k {
+			t.Errorf("Rank(%d) = %d, want %d", v, r, k)
+		}
+		if s, ok := a.Select(k); !ok || s != v {
+			t.Errorf("Select(%d) = %d, %v, want %d", k, s, ok, v)
+		}
+	}
+	if _, ok := a.Select(len(vals)); ok {
+		t.Error("Select past the end succeeded")
+	}
+	if !Equal(a, a.Clone()) || Equal(a, b) {
+		t.Error("Equal is wrong")
+	}
+}
+
+func TestString(t *testing.T) {
+	vals := []int{5, 10, 2, 5, 8, 7, 3, 9, 1, 2, 10}
+	if got := FromSlice(vals).String(); got != "{1 2 3 5 7 8 9 10}" {
+		t.Errorf("String = %s", got)
+	}
+}
+
+// the benchmarks use sets of 10,000 values below 100,000, a tenth full, and compare with
+// map[int]bool where the map has something to compare.
+
+const (
+	benchN     = 10_000
+	benchLimit = 100_000
+)
+
+var (
+	benchA, benchAMap = random(3, benchN, benchLimit)
+	benchB, benchBMap = random(4, benchN, benchLimit)
+)
+
+func BenchmarkUnion(b *testing.B) {
+	for b.Loop() {
+		Union(benchA, benchB)
+	}
+}
+
+func BenchmarkUnionMap(b *testing.B) {
+	for b.Loop() {
+		m := make(map[int]bool, len(benchAMap)+len(benchBMap))
+		for v := range benchAMap {
+			m[v] = true
+		}
+		for v := range benchBMap {
+			m[v] = true
+		}
+	}
+}
+
+func BenchmarkIntersection(b *testing.B) {
+	for b.Loop() {
+		Intersection(benchA, benchB)
+	}
+}
+
+func BenchmarkIntersectionMap(b *testing.B) {
+	for b.Loop() {
+		m := map[int]bool{}
+		for v := range benchAMap {
+			if benchBMap[v] {
+				m[v] = true
+			}
+		}
+	}
+}
+
+func BenchmarkDifference(b *testing.B) {
+	for b.Loop() {
+		Difference(benchA, benchB)
+	}
+}
+
+func BenchmarkSymmetricDifferenceWith(b *testing.B) {
+	s := benchA.Clone()
+	for b.Loop() {
+		s.SymmetricDifferenceWith(benchB) // twice is the identity, so s stays the same size
+	}
+}
+
+func BenchmarkContains(b *testing.B) {
+	i := 0
+	for b.Loop() {
+		benchA.Contains(i % benchLimit)
+		i += 7919
+	}
+}
+
+func BenchmarkContainsMap(b *testing.B) {
+	i := 0
+	for b.Loop() {
+		_ = benchAMap[i%benchLimit]
+		i += 7919
+	}
+}
+
+func BenchmarkRank(b *testing.B) {
+	i := 0
+	for b.Loop() {
+		benchA.Rank(i % benchLimit)
+		i += 7919
+	}
+}
+
+func BenchmarkSelect(b *testing.B) {
+	n := benchA.Len()
+	k := 0
+	for b.Loop() {
+		benchA.Select(k % n)
+		k += 7919
+	}
+}
+
+func BenchmarkAll(b *testing.B) {
+	for b.Loop() {
+		for range benchA.All() {
+		}
+	}
+}
+
+// ranging over the map doesn't even come out sorted; the bitset does.
+func BenchmarkAllMap(b *testing.B) {
+	for b.Loop() {
+		for range benchAMap {
+		}
+	}
+}
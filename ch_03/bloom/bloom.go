@@ -0,0 +1,206 @@
+// Package bloom is a set that never forgets a member but sometimes claims one it never saw.
+//
+// mapsInGo() builds sets as map[int]bool, which grow by a hash entry for every member. a Bloom
+// filter is a fixed array of bits: adding a value sets k of them, and a value whose k bits are all
+// set is "maybe in the set". there are no false negatives, and the chance of a false positive is
+// picked up front:
+//
+//	f := bloom.New(1_000_000, 0.01, bloom.String) // ~1.2 MB, 1% false positives
+//	f.Add("Laseen")
+//	f.Test("Laseen")  // true
+//	f.Test("Apsalar") // false, or (1 time in 100) true
+//
+// values can't be removed or listed, only tested.
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// Hasher turns a value into 64 well-mixed bits. filters that are serialised or combined must use
+// the same Hasher, so it has to give the same answer in every process: a maphash with a random
+// seed won't do.
+type Hasher[T any] func(T) uint64
+
+// String hashes a string with 64-bit FNV-1a.
+func String(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return mix(h.Sum64())
+}
+
+// Bytes hashes a byte slice the same way String does.
+func Bytes(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	return mix(h.Sum64())
+}
+
+// Int hashes an int. ints are their own hash in a map, but here neighbouring values have to land
+// far apart, so they're scrambled.
+func Int(n int) uint64 { return mix(uint64(n)) }
+
+// mix is the splitmix64 finaliser: every input bit affects every output bit.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// Filter is a Bloom filter over values of type T. it isn't safe for concurrent use.
+type Filter[T any] struct {
+	hash  Hasher[T]
+	words []uint64
+	m     uint64 // bits
+	k     uint32 // hashes per value
+	added uint64
+}
+
+// New returns a filter sized to hold n values with a false positive rate of about p, which must be
+// between 0 and 1.
+func New[T any](n int, p float64, hash Hasher[T]) *Filter[T] {
+	m, k := Size(n, p)
+	return newFilter(m, k, hash)
+}
+
+// maxK caps the hashes per value. k = -log2(p), so 64 covers any rate above 1 in 2^64; past that
+// the filter is all hashing and no benefit, and Decode won't spin on a k edited to 4 billion.
+const maxK = 64
+
+// Size is the number of bits and hashes New picks for n values at false positive rate p:
+// m = -n·ln(p) / ln(2)², k = m/n · ln(2), at most 64.
+func Size(n int, p float64) (m uint64, k uint32) {
+	if n < 1 {
+		n = 1
+	}
+	if !(p > 0 && p < 1) {
+		panic(fmt.Sprintf("bloom: false positive rate %v isn't between 0 and 1", p))
+	}
+	mf := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	m = max(uint64(mf), 64)
+	k = uint32(min(maxK, max(1, math.Round(float64(m)/float64(n)*math.Ln2))))
+	return m, k
+}
+
+func newFilter[T any](m uint64, k uint32, hash Hasher[T]) *Filter[T] {
+	return &Filter[T]{hash: hash, words: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// the k bit positions come from two hashes, h1 + i·h2 (Kirsch and Mitzenmacher), which is as good
+// as k independent hashes and much cheaper.
+func (f *Filter[T]) positions(v T, fn func(bit uint64) bool) {
+	h1 := f.hash(v)
+	h2 := mix(h1) | 1
+	for i := range uint64(f.k) {
+		if !fn((h1 + i*h2) % f.m) {
+			return
+		}
+	}
+}
+
+// Add puts v in the filter.
+func (f *Filter[T]) Add(v T) {
+	f.positions(v, func(bit uint64) bool {
+		f.words[bit/64] |= 1 << (bit % 64)
+		return true
+	})
+	f.added++
+}
+
+// Test reports whether v may have been added. false is certain; true is wrong at about the rate
+// the filter was sized for, as long as it holds no more values than it was sized for.
+func (f *Filter[T]) Test(v T) bool {
+	found := true
+	f.positions(v, func(bit uint64) bool {
+		found = f.words[bit/64]&(1<<(bit%64)) != 0
+		return found
+	})
+	return found
+}
+
+// M is the number of bits and K the number of hashes per value.
+func (f *Filter[T]) M() uint64 { return f.m }
+func (f *Filter[T]) K() uint32 { return f.k }
+
+// Added is how many times Add was called, duplicates included.
+func (f *Filter[T]) Added() uint64 { return f.added }
+
+// FalsePositiveRate estimates the current false positive rate from how full the filter is: the
+// chance that k random bits are all set.
+func (f *Filter[T]) FalsePositiveRate() float64 {
+	set := 0
+	for _, w := range f.words {
+		set += bits.OnesCount64(w)
+	}
+	return math.Pow(float64(set)/float64(f.m), float64(f.k))
+}
+
+// ErrMismatch is returned when combining filters of different shapes.
+var ErrMismatch = errors.New("bloom: filters have different sizes or hash counts")
+
+// Union adds everything in o to f, as if every value added to o had been added to f. both must
+// have the same M and K and use the same Hasher.
+func (f *Filter[T]) Union(o *Filter[T]) error {
+	if f.m != o.m || f.k != o.k {
+		return ErrMismatch
+	}
+	for i, w := range o.words {
+		f.words[i] |= w
+	}
+	f.added += o.added
+	return nil
+}
+
+const magic = "BLM1"
+
+// MarshalBinary writes the filter as "BLM1", then k, m and the added count, then the bits, all
+// little-endian. the Hasher isn't included; Decode has to be given the same one.
+func (f *Filter[T]) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, len(magic)+4+8+8+8*len(f.words))
+	b = append(b, magic...)
+	b = binary.LittleEndian.AppendUint32(b, f.k)
+	b = binary.LittleEndian.AppendUint64(b, f.m)
+	b = binary.LittleEndian.AppendUint64(b, f.added)
+	for _, w := range f.words {
+		b = binary.LittleEndian.AppendUint64(b, w)
+	}
+	return b, nil
+}
+
+// Decode reads a filter written by MarshalBinary.
+func Decode[T any](data []byte, hash Hasher[T]) (*Filter[T], error) {
+	const header = len(magic) + 4 + 8 + 8
+	if len(data) < header || string(data[:len(magic)]) != magic {
+		return nil, errors.New("bloom: not a serialised filter")
+	}
+	k := binary.LittleEndian.Uint32(data[4:])
+	m := binary.LittleEndian.Uint64(data[8:])
+	added := binary.LittleEndian.Uint64(data[16:])
+	if k == 0 || m == 0 {
+		return nil, errors.New("bloom: serialised filter has no bits or no hashes")
+	}
+	if k > maxK {
+		return nil, fmt.Errorf("bloom: serialised filter has %d hashes per value, more than %d", k, maxK)
+	}
+	// m comes from the input, so it's checked against what's there before any arithmetic on it:
+	// (m+63)/64 wraps to 0 for m near 2^64, and a filter with no words would panic in Test.
+	words := data[header:]
+	if m/64 > uint64(len(words))/8 || uint64(len(words)) != (m+63)/64*8 {
+		return nil, fmt.Errorf("bloom: serialised filter has %d bytes of bits for %d bits", len(words), m)
+	}
+
+	f := newFilter(m, k, hash)
+	f.added = added
+	for i := range f.words {
+		f.words[i] = binary.LittleEndian.Uint64(words[8*i:])
+	}
+	return f, nil
+}
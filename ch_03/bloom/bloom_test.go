@@ -0,0 +1,175 @@
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+// TestFalsePositiveRate fills filters to the n they were sized for and measures the false positive
+// rate over a million values that were never added.
+func TestFalsePositiveRate(t *testing.T) {
+	const n, trials = 10_000, 1_000_000
+	for _, p := range []float64{0.1, 0.01, 0.001} {
+		t.Run(strconv.FormatFloat(p, 'g', -1, 64), func(t *testing.T) {
+			f := New(n, p, Int)
+			for i := range n {
+				f.Add(i)
+			}
+			for i := range n {
+				if !f.Test(i) {
+					t.Fatalf("Test(%d) = false after Add", i)
+				}
+			}
+			fp := 0
+			for i := range trials {
+				if f.Test(n + i) {
+					fp++
+				}
+			}
+			rate := float64(fp) / trials
+			t.Logf("p=%v m=%d k=%d: measured %.5f, estimated %.5f", p, f.M(), f.K(), rate, f.FalsePositiveRate())
+			// a million trials at p=0.001 is a thousand hits, so ±20% is several standard deviations.
+			if math.Abs(rate-p) > 0.2*p {
+				t.Errorf("measured false positive rate %.5f, want about %v", rate, p)
+			}
+			if est := f.FalsePositiveRate(); math.Abs(est-p) > 0.2*p {
+				t.Errorf("FalsePositiveRate() = %.5f, want about %v", est, p)
+			}
+		})
+	}
+}
+
+func TestSize(t *testing.T) {
+	// the textbook numbers: 1% takes 9.59 bits and 7 hashes per value.
+	m, k := Size(1000, 0.01)
+	if m != 9586 || k != 7 {
+		t.Errorf("Size(1000, 0.01) = %d, %d, want 9586, 7", m, k)
+	}
+	if m, _ := Size(0, 0.5); m != 64 {
+		t.Errorf("Size(0, 0.5) = %d bits, want the 64 minimum", m)
+	}
+	if _, k := Size(10, 1e-300); k != maxK {
+		t.Errorf("Size(10, 1e-300) = %d hashes, want the %d maximum", k, maxK)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Size(10, 1) didn't panic")
+		}
+	}()
+	Size(10, 1)
+}
+
+func TestStrings(t *testing.T) {
+	f := New(100, 0.01, String)
+	for _, name := range []string{"Laseen", "Apsalar", "Kalam"} {
+		f.Add(name)
+	}
+	if !f.Test("Laseen") || !f.Test("Kalam") {
+		t.Error("added name not found")
+	}
+	if f.Added() != 3 {
+		t.Errorf("Added() = %d, want 3", f.Added())
+	}
+	if String("Laseen") != Bytes([]byte("Laseen")) {
+		t.Error("String and Bytes hash the same text differently")
+	}
+}
+
+func TestUnion(t *testing.T) {
+	a, b := New(1000, 0.01, Int), New(1000, 0.01, Int)
+	for i := range 500 {
+		a.Add(i)
+		b.Add(1000 + i)
+	}
+	if err := a.Union(b); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 500 {
+		if !a.Test(i) || !a.Test(1000+i) {
+			t.Fatalf("%d or %d missing after Union", i, 1000+i)
+		}
+	}
+	if a.Added() != 1000 {
+		t.Errorf("Added() = %d, want 1000", a.Added())
+	}
+	if err := a.Union(New(1000, 0.1, Int)); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Union of different sizes = %v, want ErrMismatch", err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	f := New(1000, 0.01, Int)
+	for i := range 1000 {
+		f.Add(i * 3)
+	}
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := Decode(data, Int)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.M() != f.M() || g.K() != f.K() || g.Added() != f.Added() {
+		t.Fatalf("decoded m=%d k=%d added=%d, want %d %d %d", g.M(), g.K(), g.Added(), f.M(), f.K(), f.Added())
+	}
+	for i := range 3000 {
+		if f.Test(i) != g.Test(i) {
+			t.Fatalf("Test(%d) differs after decoding", i)
+		}
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	good, _ := New(100, 0.01, Int).MarshalBinary()
+	header := func(k uint32, m uint64, words int) []byte {
+		b := []byte(magic)
+		b = binary.LittleEndian.AppendUint32(b, k)
+		b = binary.LittleEndian.AppendUint64(b, m)
+		b = binary.LittleEndian.AppendUint64(b, 0)
+		return append(b, make([]byte, 8*words)...)
+	}
+	for name, data := range map[string][]byte{
+		"empty":             nil,
+		"short header":      good[:10],
+		"bad magic":         append([]byte("BLM2"), good[4:]...),
+		"no hashes":         header(0, 64, 1),
+		"no bits":           header(3, 0, 0),
+		"k = 65":            header(65, 64, 1),
+		"k = 2^32-1":        header(math.MaxUint32, 64, 1),
+		"missing words":     good[:len(good)-8],
+		"extra words":       append(good[:len(good):len(good)], make([]byte, 8)...),
+		"partial word":      good[:len(good)-3],
+		"m = 2^64-1":        header(3, math.MaxUint64, 0),
+		"m = 2^64-64":       header(3, math.MaxUint64-63, 0),
+		"m wraps to 1 word": header(3, math.MaxUint64-62, 1),
+	} {
+		if f, err := Decode(data, Int); err == nil {
+			t.Errorf("%s: Decode succeeded with m=%d and %d words", name, f.M(), len(f.words))
+		}
+	}
+}
+
+func BenchmarkAdd(b *testing.B) {
+	f := New(1_000_000, 0.01, Int)
+	i := 0
+	for b.Loop() {
+		f.Add(i)
+		i++
+	}
+}
+
+func BenchmarkTest(b *testing.B) {
+	f := New(1_000_000, 0.01, Int)
+	for i := range 1_000_000 {
+		f.Add(i)
+	}
+	i := 0
+	for b.Loop() {
+		f.Test(i)
+		i++
+	}
+}
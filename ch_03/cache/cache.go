@@ -0,0 +1,274 @@
+// Package cache is a map with a size limit and, optionally, an expiry time on each entry.
+//
+// mapsInGo() uses maps to associate values with keys, and a map only ever grows. an LRU keeps at
+// most Capacity entries and makes room by throwing out the one that was used longest ago. it's
+// built on orderedmap: the order is recency instead of insertion, so a hit is a MoveToBack and an
+// eviction takes the Front.
+//
+// time comes from Options.Now, so a test can drive expiry with a fake clock instead of sleeping.
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"ch_03/orderedmap"
+)
+
+// Reason is why an entry left the cache.
+type Reason int
+
+const (
+	Evicted Reason = iota // pushed out to stay under Capacity
+	Expired               // its TTL ran out
+	Deleted               // Delete or Clear
+)
+
+func (r Reason) String() string {
+	switch r {
+	case Evicted:
+		return "evicted"
+	case Expired:
+		return "expired"
+	case Deleted:
+		return "deleted"
+	}
+	return fmt.Sprintf("Reason(%d)", int(r))
+}
+
+// Options configure a cache.
+type Options[K comparable, V any] struct {
+	Capacity int           // most entries held at once; 0 means no limit
+	TTL      time.Duration // how long an entry lives after Set; 0 means forever
+	// Now is the clock. nil means time.Now.
+	Now func() time.Time
+	// OnEvict, if set, is called for every entry that leaves the cache, except by being
+	// overwritten with Set.
+	OnEvict func(k K, v V, why Reason)
+}
+
+// Stats count what the cache has done since it was made.
+type Stats struct {
+	Hits, Misses        int
+	Evictions, Expiries int
+}
+
+// HitRate is hits over lookups, or 0 before the first lookup.
+func (s Stats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+type item[V any] struct {
+	value   V
+	expires time.Time // zero means never
+}
+
+// LRU is a least-recently-used cache. the zero value is an empty cache with no limit and no
+// expiry. it isn't safe for concurrent use; Sync is.
+type LRU[K comparable, V any] struct {
+	opts  Options[K, V]
+	items orderedmap.OrderedMap[K, item[V]] // oldest first
+	stats Stats
+	// nothing expires before soonest, so until then making room never has to look for expired
+	// entries. zero means no entry has a TTL.
+	soonest time.Time
+}
+
+// New returns an empty cache.
+func New[K comparable, V any](opts Options[K, V]) *LRU[K, V] {
+	return &LRU[K, V]{opts: opts}
+}
+
+func (c *LRU[K, V]) now() time.Time {
+	if c.opts.Now == nil {
+		return time.Now()
+	}
+	return c.opts.Now()
+}
+
+// Get returns the value for k and marks it as just used. an expired entry is removed and counts
+// as a miss.
+func (c *LRU[K, V]) Get(k K) (V, bool) {
+	it, ok := c.items.Get(k)
+	if ok && c.expired(it) {
+		c.remove(k, it, Expired)
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	c.stats.Hits++
+	c.items.MoveToBack(k)
+	return it.value, true
+}
+
+// Peek is Get without marking k as used or touching the stats.
+func (c *LRU[K, V]) Peek(k K) (V, bool) {
+	it, ok := c.items.Get(k)
+	if !ok || c.expired(it) {
+		var zero V
+		return zero, false
+	}
+	return it.value, true
+}
+
+// Set stores v under k with the default TTL. if that makes the cache too big, expired entries are
+// dropped first, and only if there are none is the least recently used entry evicted.
+func (c *LRU[K, V]) Set(k K, v V) { c.SetTTL(k, v, c.opts.TTL) }
+
+// SetTTL is Set with a TTL for this entry only. 0 means it never expires.
+func (c *LRU[K, V]) SetTTL(k K, v V, ttl time.Duration) {
+	it := item[V]{value: v}
+	if ttl > 0 {
+		it.expires = c.now().Add(ttl)
+		if c.soonest.IsZero() || it.expires.Before(c.soonest) {
+			c.soonest = it.expires
+		}
+	}
+	if !c.items.Set(k, it) {
+		c.items.MoveToBack(k)
+		return
+	}
+	if c.opts.Capacity <= 0 || c.items.Len() <= c.opts.Capacity {
+		return
+	}
+	if !c.soonest.IsZero() && !c.now().Before(c.soonest) {
+		// something may have expired: clearing those out might be enough.
+		if c.RemoveExpired(); c.items.Len() <= c.opts.Capacity {
+			return
+		}
+	}
+	oldest, old, _ := c.items.Front()
+	c.remove(oldest, old, Evicted)
+}
+
+// Delete removes k and reports whether it was there.
+func (c *LRU[K, V]) Delete(k K) bool {
+	it, ok := c.items.Get(k)
+	if ok {
+		c.remove(k, it, Deleted)
+	}
+	return ok
+}
+
+// Clear removes every entry. the stats are kept.
+func (c *LRU[K, V]) Clear() {
+	for k, it := range c.items.All() {
+		c.remove(k, it, Deleted)
+	}
+	c.soonest = time.Time{}
+}
+
+// RemoveExpired drops every expired entry and returns how many there were. entries are also
+// dropped lazily when they're looked up or when the cache needs room, so calling this is only
+// needed to free memory sooner.
+func (c *LRU[K, V]) RemoveExpired() int {
+	n := 0
+	c.soonest = time.Time{}
+	for k, it := range c.items.All() {
+		switch {
+		case c.expired(it):
+			c.remove(k, it, Expired)
+			n++
+		case !it.expires.IsZero() && (c.soonest.IsZero() || it.expires.Before(c.soonest)):
+			c.soonest = it.expires
+		}
+	}
+	return n
+}
+
+// Len is the number of entries, counting expired ones that haven't been dropped yet.
+func (c *LRU[K, V]) Len() int { return c.items.Len() }
+
+// Stats returns the counters so far.
+func (c *LRU[K, V]) Stats() Stats { return c.stats }
+
+func (c *LRU[K, V]) expired(it item[V]) bool {
+	return !it.expires.IsZero() && !c.now().Before(it.expires)
+}
+
+func (c *LRU[K, V]) remove(k K, it item[V], why Reason) {
+	c.items.Delete(k)
+	switch why {
+	case Evicted:
+		c.stats.Evictions++
+	case Expired:
+		c.stats.Expiries++
+	}
+	if c.opts.OnEvict != nil {
+		c.opts.OnEvict(k, it.value, why)
+	}
+}
+
+// Sync is an LRU guarded by a mutex. every method takes the lock, even Get, because a hit
+// reorders the entries. OnEvict runs with the lock held, so it must not call back into the cache.
+// like LRU, the zero value is an unlimited cache with no expiry.
+type Sync[K comparable, V any] struct {
+	mu  sync.Mutex
+	lru LRU[K, V]
+}
+
+// NewSync returns an empty concurrency-safe cache.
+func NewSync[K comparable, V any](opts Options[K, V]) *Sync[K, V] {
+	return &Sync[K, V]{lru: LRU[K, V]{opts: opts}}
+}
+
+func (c *Sync[K, V]) Get(k K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Get(k)
+}
+
+func (c *Sync[K, V]) Peek(k K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Peek(k)
+}
+
+func (c *Sync[K, V]) Set(k K, v V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Set(k, v)
+}
+
+func (c *Sync[K, V]) SetTTL(k K, v V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.SetTTL(k, v, ttl)
+}
+
+func (c *Sync[K, V]) Delete(k K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Delete(k)
+}
+
+func (c *Sync[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Clear()
+}
+
+func (c *Sync[K, V]) RemoveExpired() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.RemoveExpired()
+}
+
+func (c *Sync[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *Sync[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Stats()
+}
@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// clock is a fake time source that only moves when told to.
+type clock struct{ t time.Time }
+
+func (c *clock) Now() time.Time          { return c.t }
+func (c *clock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newClock() *clock { return &clock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)} }
+
+type event struct {
+	key string
+	why Reason
+}
+
+func TestLRUOrder(t *testing.T) {
+	var events []event
+	c := New(Options[string, int]{
+		Capacity: 2,
+		OnEvict:  func(k string, _ int, why Reason) { events = append(events, event{k, why}) },
+	})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // b is now the least recently used
+	c.Set("c", 3)
+	if _, ok := c.Peek("b"); ok {
+		t.Error("b wasn't evicted")
+	}
+	c.Set("a", 10) // overwriting isn't an eviction, and makes a the newest
+	c.Set("d", 4)
+	if want := []event{{"b", Evicted}, {"c", Evicted}}; !slices.Equal(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+	if v, ok := c.Get("a"); !ok || v != 10 {
+		t.Errorf("a = %d, %v", v, ok)
+	}
+	if s := c.Stats(); s.Evictions != 2 || s.Hits != 2 || s.Misses != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	clk := newClock()
+	var events []event
+	c := New(Options[string, int]{
+		TTL:     time.Minute,
+		Now:     clk.Now,
+		OnEvict: func(k string, _ int, why Reason) { events = append(events, event{k, why}) },
+	})
+	c.Set("short", 1)
+	c.SetTTL("long", 2, time.Hour)
+	c.SetTTL("forever", 3, 0)
+
+	clk.Advance(59 * time.Second)
+	if _, ok := c.Get("short"); !ok {
+		t.Error("short expired early")
+	}
+	clk.Advance(time.Second)
+	if _, ok := c.Get("short"); ok {
+		t.Error("short didn't expire at its TTL")
+	}
+	if _, ok := c.Peek("long"); !ok {
+		t.Error("long expired with the default TTL")
+	}
+
+	clk.Advance(time.Hour)
+	if n := c.RemoveExpired(); n != 1 {
+		t.Errorf("RemoveExpired = %d, want 1", n)
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len = %d, want only forever left", c.Len())
+	}
+	if want := []event{{"short", Expired}, {"long", Expired}}; !slices.Equal(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+	if s := c.Stats(); s.Expiries != 2 || s.Misses != 1 || s.HitRate() != 0.5 {
+		t.Errorf("stats = %+v, hit rate %v", s, s.HitRate())
+	}
+}
+
+// when the cache is full an expired entry makes room before a live one is evicted, even if the
+// expired one was used more recently.
+func TestExpiredEvictedFirst(t *testing.T) {
+	clk := newClock()
+	var events []event
+	c := New(Options[string, int]{
+		Capacity: 3,
+		Now:      clk.Now,
+		OnEvict:  func(k string, _ int, why Reason) { events = append(events, event{k, why}) },
+	})
+	c.Set("old", 1) // no TTL, least recently used
+	c.SetTTL("brief", 2, time.Second)
+	c.Set("new", 3)
+
+	clk.Advance(2 * time.Second)
+	c.Set("newer", 4)
+	if want := []event{{"brief", Expired}}; !slices.Equal(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+	if _, ok := c.Peek("old"); !ok {
+		t.Error("old was evicted while an expired entry was there")
+	}
+
+	// nothing has expired now, so the least recently used goes.
+	c.Set("newest", 5)
+	if want := []event{{"brief", Expired}, {"old", Evicted}}; !slices.Equal(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	var c LRU[string, int]
+	c.SetTTL("a", 1, time.Hour) // uses time.Now
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("Get = %d, %v", v, ok)
+	}
+
+	var s Sync[string, int]
+	s.Set("b", 2)
+	if s.Len() != 1 {
+		t.Errorf("Sync Len = %d", s.Len())
+	}
+}
+
+func TestClearAndDelete(t *testing.T) {
+	var deleted []string
+	c := New(Options[int, int]{OnEvict: func(k, _ int, why Reason) {
+		if why == Deleted {
+			deleted = append(deleted, fmt.Sprint(k))
+		}
+	}})
+	for i := range 3 {
+		c.Set(i, i)
+	}
+	if !c.Delete(1) || c.Delete(1) {
+		t.Error("Delete reported wrongly")
+	}
+	c.Clear()
+	if c.Len() != 0 || !slices.Equal(deleted, []string{"1", "0", "2"}) {
+		t.Errorf("Len = %d, deleted %v", c.Len(), deleted)
+	}
+}
+
+func TestSyncConcurrent(t *testing.T) {
+	clk := newClock()
+	var mu sync.Mutex
+	now := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return clk.Now()
+	}
+	c := NewSync(Options[int, int]{Capacity: 50, TTL: time.Second, Now: now})
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Go(func() {
+			for i := range 1000 {
+				k := (g*1000 + i) % 100
+				if _, ok := c.Get(k); !ok {
+					c.Set(k, i)
+				}
+				if i%100 == 0 {
+					mu.Lock()
+					clk.Advance(100 * time.Millisecond)
+					mu.Unlock()
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if c.Len() > 50 {
+		t.Errorf("Len = %d, over capacity", c.Len())
+	}
+	if s := c.Stats(); s.Hits+s.Misses != 8000 {
+		t.Errorf("%d lookups counted, want 8000", s.Hits+s.Misses)
+	}
+}
@@ -0,0 +1,199 @@
+// Package clone makes deep copies, so a copy never shares memory with the original.
+//
+// slicesInGo() finds that s6Y := s6X[:2] writes straight through to s6X, and that copy() is the way
+// out. copy only goes one level down though: a map of slices, or a struct holding a slice, still
+// shares everything underneath. DeepClone copies all the way down:
+//
+//	c := clone.DeepClone(totalWins)
+//	c["Laseen"] = 99 // totalWins is untouched
+//
+// nil stays nil and empty stays empty, cycles and shared pointers come out as cycles and shared
+// pointers in the copy, and unexported fields are copied too.
+package clone
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Cloner lets a type copy itself. DeepClone calls Clone instead of walking a value whose type has
+// a Clone method returning that same type, which is how types holding things reflection shouldn't
+// copy (a mutex, a file) get a say.
+//
+// the exception is the type DeepClone was called with (and, for a pointer, the type it points to):
+// values of that type are always walked. that's what lets a Clone method start from DeepClone
+// without calling itself forever:
+//
+//	func (c Config) Clone() Config {
+//		cp := clone.DeepClone(c) // walks c, doesn't call c.Clone
+//		cp.log = c.log           // share the logger instead of copying it
+//		return cp
+//	}
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// DeepClone returns a copy of v that shares no memory with it. functions and channels can't be
+// copied, so the copy refers to the same ones; strings are immutable and shared too.
+//
+// two pointers to the same value, or two maps or slices that are the same one, stay the same one
+// in the copy. slices that only overlap (s6X and s6Y above) become separate arrays.
+func DeepClone[T any](v T) T {
+	c := &cloner{seen: map[ref]reflect.Value{}, self: reflect.TypeFor[T]()}
+	if c.self.Kind() == reflect.Pointer {
+		c.selfElem = c.self.Elem()
+	}
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	c.into(dst, src)
+	return dst.Interface().(T)
+}
+
+// ref identifies something already copied: the address, plus the type because a struct and its
+// first field share an address, and the length because s[:2] and s[:3] share one too.
+type ref struct {
+	ptr uintptr
+	typ reflect.Type
+	n   int
+}
+
+type cloner struct {
+	seen map[ref]reflect.Value
+	// the types whose Clone methods aren't called, see Cloner.
+	self, selfElem reflect.Type
+}
+
+// into copies src into dst, which must be settable.
+func (c *cloner) into(dst, src reflect.Value) {
+	if t := src.Type(); t != c.self && t != c.selfElem {
+		if m, ok := cloneMethod(src); ok {
+			dst.Set(m.Call(nil)[0])
+			return
+		}
+	}
+
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		key := ref{src.Pointer(), src.Type(), 0}
+		if p, ok := c.seen[key]; ok {
+			dst.Set(p)
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		c.seen[key] = p
+		c.into(p.Elem(), src.Elem())
+		dst.Set(p)
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		key := ref{src.Pointer(), src.Type(), src.Len()}
+		if s, ok := c.seen[key]; ok {
+			dst.Set(s)
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		c.seen[key] = s
+		for i := range src.Len() {
+			c.into(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		key := ref{src.Pointer(), src.Type(), 0}
+		if m, ok := c.seen[key]; ok {
+			dst.Set(m)
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		c.seen[key] = m
+		k := reflect.New(src.Type().Key()).Elem()
+		e := reflect.New(src.Type().Elem()).Elem()
+		for it := src.MapRange(); it.Next(); {
+			k.SetZero()
+			e.SetZero()
+			c.into(k, addressable(it.Key()))
+			c.into(e, addressable(it.Value()))
+			m.SetMapIndex(k, e)
+		}
+		dst.Set(m)
+
+	case reflect.Array:
+		for i := range src.Len() {
+			c.into(dst.Index(i), src.Index(i))
+		}
+
+	case reflect.Struct:
+		for i := range src.NumField() {
+			c.into(writable(dst.Field(i)), readable(src.Field(i)))
+		}
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		e := reflect.New(src.Elem().Type()).Elem()
+		c.into(e, addressable(src.Elem()))
+		dst.Set(e)
+
+	default:
+		// basic types, strings, functions, channels and unsafe pointers are copied as they are.
+		dst.Set(src)
+	}
+}
+
+// cloneMethod finds a Clone method that returns v's own type, with a value or a pointer receiver.
+func cloneMethod(v reflect.Value) (reflect.Value, bool) {
+	if !v.CanInterface() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return reflect.Value{}, false
+	}
+	candidates := []reflect.Value{v}
+	if v.CanAddr() {
+		candidates = append(candidates, v.Addr())
+	}
+	for _, c := range candidates {
+		m := c.MethodByName("Clone")
+		if !m.IsValid() {
+			continue
+		}
+		if mt := m.Type(); mt.NumIn() == 0 && mt.NumOut() == 1 && mt.Out(0) == v.Type() {
+			return m, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// reflect won't Set an unexported field, or a value read from one. both come from addressable
+// memory here (a field of a struct DeepClone allocated or was handed), so they can be reached
+// through a plain pointer instead.
+func writable(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+func readable(v reflect.Value) reflect.Value {
+	if v.CanInterface() || !v.CanAddr() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// addressable copies a map entry or an interface's value somewhere addressable, so the struct
+// fields inside it can be read with readable.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	tmp := reflect.New(v.Type()).Elem()
+	tmp.Set(v)
+	return tmp
+}
@@ -0,0 +1,163 @@
+package clone
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+)
+
+type employee struct {
+	firstName string
+	lastName  string
+	id        int
+	tags      []string
+}
+
+func TestIndependent(t *testing.T) {
+	totalWins := map[string][]int{"Laseen": {6, 7}, "Apsalar": nil}
+	c := DeepClone(totalWins)
+	c["Laseen"][0] = 99
+	c["Kalam"] = []int{1}
+	if totalWins["Laseen"][0] != 6 || len(totalWins) != 2 {
+		t.Errorf("changing the copy changed the original: %v", totalWins)
+	}
+	if c["Apsalar"] != nil {
+		t.Errorf("nil slice came out as %#v", c["Apsalar"])
+	}
+
+	es := []employee{{"Ada", "Igwe", 1, []string{"a"}}}
+	ec := DeepClone(es)
+	ec[0].tags[0] = "b"
+	ec[0].lastName = "Obi"
+	if es[0].tags[0] != "a" || es[0].lastName != "Igwe" {
+		t.Errorf("unexported fields were shared: %+v", es[0])
+	}
+}
+
+func TestSharingAndCycles(t *testing.T) {
+	type node struct {
+		val  int
+		next *node
+	}
+	n := &node{val: 1}
+	n.next = n
+	c := DeepClone(n)
+	if c == n || c.next != c {
+		t.Errorf("cycle not kept: c=%p c.next=%p n=%p", c, c.next, n)
+	}
+
+	shared := []int{1, 2}
+	pair := DeepClone([2][]int{shared, shared})
+	pair[0][0] = 9
+	if pair[1][0] != 9 || shared[0] != 1 {
+		t.Errorf("shared slice not kept shared in the copy: %v, original %v", pair, shared)
+	}
+
+	empty := DeepClone([]int{})
+	if empty == nil {
+		t.Error("empty slice came out nil")
+	}
+}
+
+// config's Clone starts from DeepClone, which must walk it rather than call Clone again.
+type config struct {
+	mu    *sync.Mutex
+	names []string
+	sub   *config
+}
+
+func (c config) Clone() config {
+	cp := DeepClone(c)
+	cp.mu = c.mu // shared on purpose
+	return cp
+}
+
+func TestCloneMethodCallingDeepClone(t *testing.T) {
+	mu := &sync.Mutex{}
+	c := config{mu: mu, names: []string{"a"}, sub: &config{mu: mu, names: []string{"b"}}}
+	cp := c.Clone()
+	if cp.mu != mu {
+		t.Error("Clone didn't get to share the mutex")
+	}
+	cp.names[0], cp.sub.names[0] = "x", "y"
+	if c.names[0] != "a" || c.sub.names[0] != "b" {
+		t.Errorf("copy shares slices with the original: %v %v", c.names, c.sub.names)
+	}
+
+	// through a pointer too.
+	pc := DeepClone(&c)
+	if pc == &c || pc.sub == c.sub {
+		t.Error("DeepClone(&c) didn't copy")
+	}
+}
+
+// withClone's Clone is used when it's inside something else being cloned.
+type withClone struct{ n int }
+
+func (w withClone) Clone() withClone { return withClone{n: -w.n} }
+
+func TestCloneMethodUsed(t *testing.T) {
+	got := DeepClone([]withClone{{1}, {2}})
+	if want := []withClone{{-1}, {-2}}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := DeepClone(withClone{3}); got.n != 3 {
+		t.Errorf("DeepClone called the root's own Clone: %v", got)
+	}
+}
+
+var (
+	benchMap = func() map[string][]int {
+		m := make(map[string][]int, 100)
+		for i := range 100 {
+			m[string(rune('a'+i%26))+string(rune('a'+i/26))] = []int{i, i + 1, i + 2}
+		}
+		return m
+	}()
+	benchEmployees = func() []employee {
+		es := make([]employee, 100)
+		for i := range es {
+			es[i] = employee{"first", "last", i, []string{"x", "y"}}
+		}
+		return es
+	}()
+)
+
+func BenchmarkDeepCloneMap(b *testing.B) {
+	for b.Loop() {
+		DeepClone(benchMap)
+	}
+}
+
+// the same copy written by hand, to show what the reflection costs.
+func BenchmarkHandCloneMap(b *testing.B) {
+	for b.Loop() {
+		m := make(map[string][]int, len(benchMap))
+		for k, v := range benchMap {
+			m[k] = slices.Clone(v)
+		}
+	}
+}
+
+func BenchmarkMapsClone(b *testing.B) {
+	// shallow: the slices are shared.
+	for b.Loop() {
+		_ = maps.Clone(benchMap)
+	}
+}
+
+func BenchmarkDeepCloneStructs(b *testing.B) {
+	for b.Loop() {
+		DeepClone(benchEmployees)
+	}
+}
+
+func BenchmarkHandCloneStructs(b *testing.B) {
+	for b.Loop() {
+		es := slices.Clone(benchEmployees)
+		for i := range es {
+			es[i].tags = slices.Clone(es[i].tags)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Command padcheck reports structs whose field order wastes memory on padding.
+//
+//	go run ./cmd/padcheck ./...
+//	go run ./cmd/padcheck -goarch=386 -threshold=8 ./...
+package main
+
+import (
+	"ch_03/structlayout"
+
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(structlayout.Analyzer)
+}
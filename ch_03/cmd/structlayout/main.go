@@ -0,0 +1,80 @@
+// Command structlayout prints the memory layout of the struct types in Go packages.
+//
+//	go run ./cmd/structlayout .
+//	go run ./cmd/structlayout -goarch=386 -type=Layout,Field ./structlayout
+//
+// every package-level struct type is printed with its fields' offsets, sizes and alignments, the
+// padding between them, and a better field order when there is one. types declared inside a
+// function, like person in structs(), aren't reachable this way; padcheck looks at those too.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/types"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+
+	"ch_03/structlayout"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	goarch := flag.String("goarch", runtime.GOARCH, "lay structs out for this GOARCH")
+	typeNames := flag.String("type", "", "comma-separated struct types to print; all of them if empty")
+	dir := flag.String("C", "", "load packages relative to this directory")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: structlayout [-goarch arch] [-type T,U] [-C dir] [packages]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	sizes, err := structlayout.Sizes(*goarch)
+	if err != nil {
+		fatal(err)
+	}
+	var only []string
+	if *typeNames != "" {
+		only = strings.Split(*typeNames, ",")
+	}
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax, Dir: *dir}, patterns...)
+	if err != nil {
+		fatal(err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		os.Exit(1)
+	}
+
+	for _, pkg := range pkgs {
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || (only != nil && !slices.Contains(only, name)) {
+				continue
+			}
+			st, ok := tn.Type().Underlying().(*types.Struct)
+			if !ok {
+				continue
+			}
+			if structlayout.Generic(st) {
+				fmt.Printf("%s.%s: generic, the layout depends on the type arguments\n\n", pkg.Name, name)
+				continue
+			}
+			fmt.Printf("%s.%s: %s", pkg.Name, name, structlayout.Of(st, sizes))
+			fmt.Printf("  optimal: %s\n\n", structlayout.Describe(st, sizes))
+		}
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(2)
+}
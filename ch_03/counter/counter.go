@@ -0,0 +1,225 @@
+// Package counter counts things: a map from value to how many times it was seen.
+//
+// mapsInGo() keeps score with totalWins["Laseen"]++ and dedups vals into intSet, and in doing so
+// throws away how often each number came up. a Counter keeps both:
+//
+//	c := counter.FromSlice(vals) // []int{5, 10, 2, 5, 8, 7, 3, 9, 1, 2, 10}
+//	c.Len()          // 8, the same as len(intSet)
+//	c.Count(10)      // 2
+//	c.MostCommon(3)  // [{5 2} {10 2} {2 2}]
+//	c.Total()        // 11, the same as len(vals)
+//
+// it's a multiset, so counts are never negative: taking away more than there is leaves zero, and
+// a value with a zero count isn't in the counter at all.
+package counter
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+type item struct {
+	count int
+	seq   int // when the value was first added, to break ties in a stable way
+}
+
+// Counter counts values of type T. the zero value is an empty counter ready to use. it isn't safe
+// for concurrent use.
+type Counter[T comparable] struct {
+	items map[T]*item
+	total int
+	next  int
+}
+
+// Entry is a value and its count.
+type Entry[T comparable] struct {
+	Value T
+	Count int
+}
+
+// New returns an empty counter.
+func New[T comparable]() *Counter[T] { return &Counter[T]{} }
+
+// FromSlice counts the values in s.
+func FromSlice[T comparable](s []T) *Counter[T] {
+	return FromSeq(slices.Values(s))
+}
+
+// FromSeq counts the values seq yields.
+func FromSeq[T comparable](seq iter.Seq[T]) *Counter[T] {
+	c := New[T]()
+	for v := range seq {
+		c.Add(v)
+	}
+	return c
+}
+
+// Add counts v once.
+func (c *Counter[T]) Add(v T) { c.AddN(v, 1) }
+
+// AddN counts v n more times. n can be negative, and a count that drops to zero or below removes v.
+func (c *Counter[T]) AddN(v T, n int) {
+	if c.items == nil {
+		c.items = map[T]*item{}
+	}
+	it, ok := c.items[v]
+	if !ok {
+		if n <= 0 {
+			return
+		}
+		it = &item{seq: c.next}
+		c.next++
+		c.items[v] = it
+	}
+	if it.count+n <= 0 {
+		c.total -= it.count
+		delete(c.items, v)
+		return
+	}
+	it.count += n
+	c.total += n
+}
+
+// Remove forgets v entirely.
+func (c *Counter[T]) Remove(v T) {
+	if it, ok := c.items[v]; ok {
+		c.total -= it.count
+		delete(c.items, v)
+	}
+}
+
+// Count is how many times v was counted; 0 if never.
+func (c *Counter[T]) Count(v T) int {
+	if it, ok := c.items[v]; ok {
+		return it.count
+	}
+	return 0
+}
+
+// Len is the number of distinct values.
+func (c *Counter[T]) Len() int { return len(c.items) }
+
+// Total is the sum of all counts.
+func (c *Counter[T]) Total() int { return c.total }
+
+// All iterates over the values and their counts, in the order each value was first added.
+func (c *Counter[T]) All() iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		for _, e := range c.sorted(false) {
+			if !yield(e.Value, e.Count) {
+				return
+			}
+		}
+	}
+}
+
+// Elements yields every value as many times as it was counted, like vals before it went into
+// intSet (though grouped, not in the original order).
+func (c *Counter[T]) Elements() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v, n := range c.All() {
+			for range n {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// MostCommon returns the n values with the highest counts, highest first. ties keep the order the
+// values were first added. n < 0 returns all of them.
+func (c *Counter[T]) MostCommon(n int) []Entry[T] {
+	out := c.sorted(true)
+	if n >= 0 && n < len(out) {
+		out = out[:n]
+	}
+	return out
+}
+
+func (c *Counter[T]) sorted(byCount bool) []Entry[T] {
+	type ranked struct {
+		Entry[T]
+		seq int
+	}
+	rs := make([]ranked, 0, len(c.items))
+	for v, it := range c.items {
+		rs = append(rs, ranked{Entry[T]{v, it.count}, it.seq})
+	}
+	slices.SortFunc(rs, func(a, b ranked) int {
+		if byCount {
+			if r := cmp.Compare(b.Count, a.Count); r != 0 {
+				return r
+			}
+		}
+		return cmp.Compare(a.seq, b.seq)
+	})
+	out := make([]Entry[T], len(rs))
+	for i, r := range rs {
+		out[i] = r.Entry
+	}
+	return out
+}
+
+// Clone returns an independent copy.
+func (c *Counter[T]) Clone() *Counter[T] {
+	out := New[T]()
+	for v, n := range c.All() {
+		out.AddN(v, n)
+	}
+	return out
+}
+
+// Sum adds the counts of a and b.
+func Sum[T comparable](a, b *Counter[T]) *Counter[T] {
+	out := a.Clone()
+	for v, n := range b.All() {
+		out.AddN(v, n)
+	}
+	return out
+}
+
+// Subtract takes b's counts away from a's, dropping anything that reaches zero.
+func Subtract[T comparable](a, b *Counter[T]) *Counter[T] {
+	out := a.Clone()
+	for v, n := range b.All() {
+		out.AddN(v, -n)
+	}
+	return out
+}
+
+// Intersect keeps the values in both, each with the smaller of its two counts.
+func Intersect[T comparable](a, b *Counter[T]) *Counter[T] {
+	out := New[T]()
+	for v, n := range a.All() {
+		if m := b.Count(v); m > 0 {
+			out.AddN(v, min(n, m))
+		}
+	}
+	return out
+}
+
+// Union keeps the values in either, each with the larger of its two counts.
+func Union[T comparable](a, b *Counter[T]) *Counter[T] {
+	out := a.Clone()
+	for v, n := range b.All() {
+		if extra := n - out.Count(v); extra > 0 {
+			out.AddN(v, extra)
+		}
+	}
+	return out
+}
+
+// Equal reports whether a and b hold the same values with the same counts.
+func Equal[T comparable](a, b *Counter[T]) bool {
+	if a.Len() != b.Len() || a.Total() != b.Total() {
+		return false
+	}
+	for v, it := range a.items {
+		if b.Count(v) != it.count {
+			return false
+		}
+	}
+	return true
+}
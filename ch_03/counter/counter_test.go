@@ -0,0 +1,94 @@
+package counter
+
+import (
+	"slices"
+	"testing"
+)
+
+// vals is the slice mapsInGo() puts into intSet in composites.go.
+var vals = []int{5, 10, 2, 5, 8, 7, 3, 9, 1, 2, 10}
+
+func TestVals(t *testing.T) {
+	c := FromSlice(vals)
+	intSet := map[int]bool{}
+	for _, v := range vals {
+		intSet[v] = true
+	}
+
+	if c.Len() != len(intSet) {
+		t.Errorf("Len = %d, want len(intSet) = %d", c.Len(), len(intSet))
+	}
+	if c.Total() != len(vals) {
+		t.Errorf("Total = %d, want len(vals) = %d", c.Total(), len(vals))
+	}
+	for v, want := range map[int]int{5: 2, 10: 2, 2: 2, 8: 1, 1: 1, 500: 0} {
+		if got := c.Count(v); got != want {
+			t.Errorf("Count(%d) = %d, want %d", v, got, want)
+		}
+	}
+	if got, want := c.MostCommon(3), []Entry[int]{{5, 2}, {10, 2}, {2, 2}}; !slices.Equal(got, want) {
+		t.Errorf("MostCommon(3) = %v, want %v", got, want)
+	}
+	if got := c.MostCommon(-1); len(got) != 8 || got[7] != (Entry[int]{1, 1}) {
+		t.Errorf("MostCommon(-1) = %v", got)
+	}
+
+	var first []int
+	for v := range c.All() {
+		first = append(first, v)
+	}
+	if want := []int{5, 10, 2, 8, 7, 3, 9, 1}; !slices.Equal(first, want) {
+		t.Errorf("All in first-seen order = %v, want %v", first, want)
+	}
+
+	elems := slices.Sorted(c.Elements())
+	if want := slices.Sorted(slices.Values(vals)); !slices.Equal(elems, want) {
+		t.Errorf("Elements = %v, want vals sorted %v", elems, want)
+	}
+}
+
+func TestAddN(t *testing.T) {
+	var c Counter[string] // the zero value works
+	c.Add("Laseen")
+	c.AddN("Laseen", 5)
+	c.AddN("Kalam", -3) // never there, stays out
+	if c.Count("Laseen") != 6 || c.Len() != 1 || c.Total() != 6 {
+		t.Errorf("Count=%d Len=%d Total=%d", c.Count("Laseen"), c.Len(), c.Total())
+	}
+	c.AddN("Laseen", -10)
+	if c.Len() != 0 || c.Total() != 0 {
+		t.Errorf("taking away more than there is left Len=%d Total=%d", c.Len(), c.Total())
+	}
+	c.Add("Kalam")
+	c.Remove("Kalam")
+	if c.Count("Kalam") != 0 || c.Total() != 0 {
+		t.Error("Remove left Kalam counted")
+	}
+}
+
+func TestSetOps(t *testing.T) {
+	a := FromSlice(vals)                  // 5:2 10:2 2:2 8 7 3 9 1
+	b := FromSlice([]int{5, 5, 5, 10, 4}) // 5:3 10:1 4:1
+
+	check := func(name string, got *Counter[int], want map[int]int) {
+		t.Helper()
+		w := New[int]()
+		for v, n := range want {
+			w.AddN(v, n)
+		}
+		if !Equal(got, w) {
+			t.Errorf("%s = %v, want %v", name, got.MostCommon(-1), want)
+		}
+	}
+	check("Sum", Sum(a, b), map[int]int{5: 5, 10: 3, 2: 2, 8: 1, 7: 1, 3: 1, 9: 1, 1: 1, 4: 1})
+	check("Subtract", Subtract(a, b), map[int]int{10: 1, 2: 2, 8: 1, 7: 1, 3: 1, 9: 1, 1: 1})
+	check("Intersect", Intersect(a, b), map[int]int{5: 2, 10: 1})
+	check("Union", Union(a, b), map[int]int{5: 3, 10: 2, 2: 2, 8: 1, 7: 1, 3: 1, 9: 1, 1: 1, 4: 1})
+
+	if a.Count(5) != 2 || b.Count(5) != 3 {
+		t.Error("set operations changed their arguments")
+	}
+	if !Equal(a, a.Clone()) || Equal(a, b) {
+		t.Error("Equal is wrong")
+	}
+}
@@ -0,0 +1,336 @@
+// Package diff says how two values differ, not just whether they do.
+//
+// composites.go compares with slices.Equal and maps.Equal, which answer yes or no. Diff walks both
+// values together and returns every difference with the path to it:
+//
+//	employees[2].lastName: "Igwe" -> "Obi"
+//	totalWins["Laseen"]: 6 -> 7
+//	totalWins["Apsalar"]: added 3
+//
+// it works on any mix of slices, arrays, maps, structs, pointers and interfaces, unexported
+// fields included, and stops at cycles: a pointer, map or slice that leads back to itself.
+package diff
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"ch_03/internal/order"
+)
+
+// Kind is what happened at a path.
+type Kind int
+
+const (
+	Modified Kind = iota
+	Added
+	Removed
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Modified:
+		return "modified"
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Change is a single difference. Old is empty for additions and New for removals; both are
+// formatted with %v so unexported fields can be reported too.
+type Change struct {
+	Path string
+	Kind Kind
+	Old  string
+	New  string
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%s: added %s", c.Path, c.New)
+	case Removed:
+		return fmt.Sprintf("%s: removed %s", c.Path, c.Old)
+	}
+	return fmt.Sprintf("%s: %s -> %s", c.Path, c.Old, c.New)
+}
+
+// Option changes how values are compared.
+type Option func(*config)
+
+type config struct {
+	root         string
+	ignore       map[string]bool
+	tolerance    float64
+	nilIsEmpty   bool
+	hasTolerance bool
+}
+
+// Root names the top-level value, so paths read employees[2] instead of [2].
+func Root(name string) Option { return func(c *config) { c.root = name } }
+
+// IgnoreFields skips struct fields. a name matches that field in any struct; a path (anything
+// with a dot or bracket in it, like "employees[0].id") matches only there.
+func IgnoreFields(names ...string) Option {
+	return func(c *config) {
+		for _, n := range names {
+			c.ignore[n] = true
+		}
+	}
+}
+
+// NilEqualsEmpty treats a nil slice or map as equal to an empty one.
+func NilEqualsEmpty() Option { return func(c *config) { c.nilIsEmpty = true } }
+
+// FloatTolerance treats floats as equal when they differ by at most eps, either absolutely or
+// relative to the larger of the two. types() warns against comparing floats with ==, and this is
+// the epsilon it suggests.
+func FloatTolerance(eps float64) Option {
+	return func(c *config) { c.tolerance, c.hasTolerance = eps, true }
+}
+
+// Diff returns every difference between a and b, in the order the values are walked (map keys
+// sorted). no changes means the values are equal under the options.
+func Diff[T any](a, b T, opts ...Option) []Change {
+	c := &config{ignore: map[string]bool{}}
+	for _, o := range opts {
+		o(c)
+	}
+	w := &walker{config: c, visiting: map[visit]bool{}}
+	w.walk(c.root, reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem())
+	return w.changes
+}
+
+// Equal reports whether Diff would find nothing.
+func Equal[T any](a, b T, opts ...Option) bool { return len(Diff(a, b, opts...)) == 0 }
+
+// a pair of pointers, maps or slices being compared further up, which is how cycles are cut. slices
+// also need their lengths: s and s[:1] start at the same address but aren't the same slice.
+type visit struct {
+	a, b   uintptr
+	typ    reflect.Type
+	na, nb int
+}
+
+type walker struct {
+	*config
+	changes  []Change
+	visiting map[visit]bool
+}
+
+// enter marks the pair a, b as being compared. it reports false if it already is, which means the
+// walk has come back round a cycle and there's nothing new below.
+func (w *walker) enter(a, b reflect.Value) (visit, bool) {
+	key := visit{a: uintptr(a.UnsafePointer()), b: uintptr(b.UnsafePointer()), typ: a.Type()}
+	if a.Kind() == reflect.Slice {
+		key.na, key.nb = a.Len(), b.Len()
+	}
+	if w.visiting[key] {
+		return key, false
+	}
+	w.visiting[key] = true
+	return key, true
+}
+
+func (w *walker) add(path string, kind Kind, old, new reflect.Value) {
+	ch := Change{Path: path, Kind: kind}
+	if old.IsValid() {
+		ch.Old = format(old)
+	}
+	if new.IsValid() {
+		ch.New = format(new)
+	}
+	if ch.Path == "" {
+		ch.Path = "."
+	}
+	w.changes = append(w.changes, ch)
+}
+
+func (w *walker) walk(path string, a, b reflect.Value) {
+	if a.Type() != b.Type() {
+		w.add(path, Modified, a, b)
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() && !(w.nilIsEmpty && a.Len() == 0 && b.Len() == 0) {
+			w.add(path, Modified, a, b)
+			return
+		}
+		if a.Pointer() == b.Pointer() && a.Len() == b.Len() {
+			return
+		}
+		key, ok := w.enter(a, b)
+		if !ok {
+			return
+		}
+		defer delete(w.visiting, key)
+		w.sequence(path, a, b)
+
+	case reflect.Array:
+		w.sequence(path, a, b)
+
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() && !(w.nilIsEmpty && a.Len() == 0 && b.Len() == 0) {
+			w.add(path, Modified, a, b)
+			return
+		}
+		if a.UnsafePointer() == b.UnsafePointer() {
+			return
+		}
+		key, ok := w.enter(a, b)
+		if !ok {
+			return
+		}
+		defer delete(w.visiting, key)
+		for _, k := range order.Keys(a) {
+			kp := path + "[" + formatKey(k) + "]"
+			bv := b.MapIndex(k)
+			if !bv.IsValid() {
+				w.add(kp, Removed, a.MapIndex(k), reflect.Value{})
+				continue
+			}
+			w.walk(kp, a.MapIndex(k), bv)
+		}
+		for _, k := range order.Keys(b) {
+			if !a.MapIndex(k).IsValid() {
+				w.add(path+"["+formatKey(k)+"]", Added, reflect.Value{}, b.MapIndex(k))
+			}
+		}
+
+	case reflect.Struct:
+		t := a.Type()
+		for i := range a.NumField() {
+			name := t.Field(i).Name
+			fp := name
+			if path != "" {
+				fp = path + "." + name
+			}
+			if w.ignore[name] || w.ignore[fp] {
+				continue
+			}
+			w.walk(fp, a.Field(i), b.Field(i))
+		}
+
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				w.add(path, Modified, a, b)
+			}
+			return
+		}
+		if a.Pointer() == b.Pointer() {
+			return
+		}
+		key, ok := w.enter(a, b)
+		if !ok {
+			return
+		}
+		defer delete(w.visiting, key)
+		w.walk(path, a.Elem(), b.Elem())
+
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				w.add(path, Modified, a, b)
+			}
+			return
+		}
+		w.walk(path, a.Elem(), b.Elem())
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		// functions can't be compared; channels and unsafe pointers by identity only.
+		if a.Kind() == reflect.Func {
+			if a.IsNil() != b.IsNil() {
+				w.add(path, Modified, a, b)
+			}
+			return
+		}
+		if a.Pointer() != b.Pointer() {
+			w.add(path, Modified, a, b)
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if !w.floatsEqual(a.Float(), b.Float()) {
+			w.add(path, Modified, a, b)
+		}
+
+	case reflect.Complex64, reflect.Complex128:
+		ac, bc := a.Complex(), b.Complex()
+		if !w.floatsEqual(real(ac), real(bc)) || !w.floatsEqual(imag(ac), imag(bc)) {
+			w.add(path, Modified, a, b)
+		}
+
+	case reflect.Bool:
+		if a.Bool() != b.Bool() {
+			w.add(path, Modified, a, b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if a.Int() != b.Int() {
+			w.add(path, Modified, a, b)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if a.Uint() != b.Uint() {
+			w.add(path, Modified, a, b)
+		}
+	case reflect.String:
+		if a.String() != b.String() {
+			w.add(path, Modified, a, b)
+		}
+	}
+}
+
+// sequence compares slices and arrays index by index. anything past the end of the shorter one is
+// an addition or removal.
+func (w *walker) sequence(path string, a, b reflect.Value) {
+	n := min(a.Len(), b.Len())
+	for i := range n {
+		w.walk(path+"["+strconv.Itoa(i)+"]", a.Index(i), b.Index(i))
+	}
+	for i := n; i < a.Len(); i++ {
+		w.add(path+"["+strconv.Itoa(i)+"]", Removed, a.Index(i), reflect.Value{})
+	}
+	for i := n; i < b.Len(); i++ {
+		w.add(path+"["+strconv.Itoa(i)+"]", Added, reflect.Value{}, b.Index(i))
+	}
+}
+
+func (w *walker) floatsEqual(a, b float64) bool {
+	if a == b || (math.IsNaN(a) && math.IsNaN(b)) {
+		return true
+	}
+	if !w.hasTolerance {
+		return false
+	}
+	d := math.Abs(a - b)
+	return d <= w.tolerance || d <= w.tolerance*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// format prints a value for a Change. fmt can print reflect.Values from unexported fields, which
+// Interface() can't.
+func format(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Slice, reflect.Map, reflect.Pointer, reflect.Interface, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return "nil"
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func formatKey(k reflect.Value) string {
+	s := format(k)
+	if k.Kind() == reflect.Interface && !k.IsNil() {
+		s = format(k.Elem())
+	}
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
@@ -0,0 +1,95 @@
+package diff
+
+import (
+	"slices"
+	"testing"
+)
+
+type employee struct {
+	firstName string
+	lastName  string
+	id        int
+}
+
+func strs(cs []Change) []string {
+	out := make([]string, len(cs))
+	for i, c := range cs {
+		out[i] = c.String()
+	}
+	return out
+}
+
+func check(t *testing.T, got []Change, want ...string) {
+	t.Helper()
+	if g := strs(got); !slices.Equal(g, want) {
+		t.Errorf("got changes\n%q\nwant\n%q", g, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := []employee{{"Ada", "Igwe", 1}, {"Bo", "Obi", 2}}
+	b := []employee{{"Ada", "Obi", 1}, {"Bo", "Obi", 2}, {"Cy", "Eze", 3}}
+	check(t, Diff(a, b, Root("employees")),
+		`employees[0].lastName: "Igwe" -> "Obi"`,
+		`employees[2]: added {Cy Eze 3}`,
+	)
+
+	check(t, Diff(map[string]int{"Laseen": 6, "Kalam": 2}, map[string]int{"Laseen": 7, "Apsalar": 3}, Root("totalWins")),
+		`totalWins["Kalam"]: removed 2`,
+		`totalWins["Laseen"]: 6 -> 7`,
+		`totalWins["Apsalar"]: added 3`,
+	)
+}
+
+func TestOptions(t *testing.T) {
+	a := []employee{{"Ada", "Igwe", 1}}
+	b := []employee{{"Ada", "Igwe", 9}}
+	if !Equal(a, b, IgnoreFields("id")) {
+		t.Error("IgnoreFields(id) still found a difference")
+	}
+	if Equal(a, b, IgnoreFields("[1].id")) {
+		t.Error("IgnoreFields with a path that doesn't match hid the difference")
+	}
+
+	if Equal([]int(nil), []int{}) {
+		t.Error("nil and empty slices compared equal without NilEqualsEmpty")
+	}
+	if !Equal([]int(nil), []int{}, NilEqualsEmpty()) {
+		t.Error("nil and empty slices differ with NilEqualsEmpty")
+	}
+
+	x, y := 0.1, 0.2
+	if Equal(x+y, 0.3) {
+		t.Error("0.1+0.2 == 0.3 without a tolerance")
+	}
+	if !Equal(x+y, 0.3, FloatTolerance(1e-9)) {
+		t.Error("0.1+0.2 != 0.3 with a tolerance")
+	}
+}
+
+func TestCycles(t *testing.T) {
+	m1 := map[string]any{"x": 1}
+	m1["self"] = m1
+	m2 := map[string]any{"x": 2}
+	m2["self"] = m2
+	check(t, Diff(m1, m2), `["x"]: 1 -> 2`)
+
+	s1, s2 := make([]any, 2), make([]any, 2)
+	s1[0], s1[1] = s1, "a"
+	s2[0], s2[1] = s2, "b"
+	check(t, Diff(s1, s2), `[1]: "a" -> "b"`)
+
+	type node struct {
+		val  int
+		next *node
+	}
+	n1, n2 := &node{val: 1}, &node{val: 2}
+	n1.next, n2.next = n1, n2
+	check(t, Diff(n1, n2), `val: 1 -> 2`)
+}
+
+// the same slice reached at two paths isn't a cycle, so the difference is reported at both.
+func TestSharedNotCycle(t *testing.T) {
+	x, y := []int{1}, []int{2}
+	check(t, Diff([][]int{x, x}, [][]int{y, y}), `[0][0]: 1 -> 2`, `[1][0]: 1 -> 2`)
+}
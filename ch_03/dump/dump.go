@@ -0,0 +1,274 @@
+// Package dump prints any value as an indented tree.
+//
+// every function in composites.go prints with fmt.Println, which hides the things the chapter is
+// about: fmt prints a nil slice and an empty one both as [], doesn't show capacity, drops struct
+// field names and prints maps without saying how many entries they hold. Dump shows all of it:
+//
+//	[]float64 len=9 cap=9
+//	  [0] float64 1.2
+//	  [1] float64 5.8
+//	  ...
+//
+// unexported struct fields are shown too, map keys are sorted so the output is the same every run,
+// and cycles, through pointers or through maps and slices that hold themselves, are cut off instead
+// of looping forever.
+package dump
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"ch_03/internal/order"
+)
+
+// Options tune the output. the zero value is a sensible default.
+type Options struct {
+	MaxDepth int    // how many levels to expand; 0 means no limit
+	Indent   string // per level; defaults to two spaces
+	Color    bool   // ANSI colours for types, strings, numbers and nil
+}
+
+// Sdump returns the tree for v.
+func Sdump(v any, opts Options) string {
+	var b strings.Builder
+	Fdump(&b, v, opts)
+	return b.String()
+}
+
+// Fdump writes the tree for v to w.
+func Fdump(w io.Writer, v any, opts Options) error {
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	d := &dumper{opts: opts, visiting: map[visit]bool{}}
+	if v == nil {
+		d.line(0, "", d.paint(red, "nil"))
+	} else {
+		d.value(0, "", reflect.ValueOf(v))
+	}
+	_, err := io.WriteString(w, d.b.String())
+	return err
+}
+
+// Dump prints the tree for v to standard output, with default options.
+func Dump(v any) { Fdump(os.Stdout, v, Options{}) }
+
+// a pointer, map or slice is only a cycle if the same address is being visited with the same type
+// (and, for a slice, the same length: s[:1] inside s isn't a loop).
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	n   int
+}
+
+type dumper struct {
+	b        strings.Builder
+	opts     Options
+	visiting map[visit]bool
+}
+
+const (
+	reset  = "\x1b[0m"
+	red    = "\x1b[31m"
+	green  = "\x1b[32m"
+	yellow = "\x1b[33m"
+	cyan   = "\x1b[36m"
+	grey   = "\x1b[90m"
+)
+
+func (d *dumper) paint(color, s string) string {
+	if !d.opts.Color {
+		return s
+	}
+	return color + s + reset
+}
+
+func (d *dumper) line(depth int, label, text string) {
+	d.b.WriteString(strings.Repeat(d.opts.Indent, depth))
+	if label != "" {
+		d.b.WriteString(label)
+		d.b.WriteByte(' ')
+	}
+	d.b.WriteString(text)
+	d.b.WriteByte('\n')
+}
+
+func (d *dumper) typeName(t reflect.Type) string { return d.paint(cyan, t.String()) }
+
+func (d *dumper) value(depth int, label string, v reflect.Value) {
+	t := v.Type()
+	tooDeep := d.opts.MaxDepth > 0 && depth >= d.opts.MaxDepth
+
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			d.line(depth, label, d.typeName(t)+" "+d.paint(red, "nil"))
+			return
+		}
+		key, ok := d.enter(v)
+		if !ok {
+			d.line(depth, label, fmt.Sprintf("%s len=%d cap=%d %s", d.typeName(t), v.Len(), v.Cap(), d.paint(red, "<cycle>")))
+			return
+		}
+		defer delete(d.visiting, key)
+		d.line(depth, label, fmt.Sprintf("%s len=%d cap=%d%s", d.typeName(t), v.Len(), v.Cap(), emptyMark(v.Len())))
+		if !tooDeep {
+			d.elements(depth+1, v)
+		}
+
+	case reflect.Array:
+		d.line(depth, label, fmt.Sprintf("%s len=%d", d.typeName(t), v.Len()))
+		if !tooDeep {
+			d.elements(depth+1, v)
+		}
+
+	case reflect.Map:
+		if v.IsNil() {
+			d.line(depth, label, d.typeName(t)+" "+d.paint(red, "nil"))
+			return
+		}
+		key, ok := d.enter(v)
+		if !ok {
+			d.line(depth, label, fmt.Sprintf("%s len=%d %s", d.typeName(t), v.Len(), d.paint(red, "<cycle>")))
+			return
+		}
+		defer delete(d.visiting, key)
+		d.line(depth, label, fmt.Sprintf("%s len=%d%s", d.typeName(t), v.Len(), emptyMark(v.Len())))
+		if tooDeep {
+			return
+		}
+		for _, k := range order.Keys(v) {
+			d.value(depth+1, d.scalar(k)+":", v.MapIndex(k))
+		}
+
+	case reflect.Struct:
+		d.line(depth, label, d.typeName(t))
+		if tooDeep {
+			return
+		}
+		for i := range v.NumField() {
+			name := t.Field(i).Name
+			if !t.Field(i).IsExported() {
+				name = d.paint(grey, name)
+			}
+			d.value(depth+1, name+":", v.Field(i))
+		}
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			d.line(depth, label, d.typeName(t)+" "+d.paint(red, "nil"))
+			return
+		}
+		key, ok := d.enter(v)
+		if !ok {
+			d.line(depth, label, fmt.Sprintf("%s %#x %s", d.typeName(t), v.Pointer(), d.paint(red, "<cycle>")))
+			return
+		}
+		defer delete(d.visiting, key)
+		d.line(depth, label, fmt.Sprintf("%s %#x", d.typeName(t), v.Pointer()))
+		if !tooDeep {
+			d.value(depth+1, "→", v.Elem())
+		}
+
+	case reflect.Interface:
+		if v.IsNil() {
+			d.line(depth, label, d.typeName(t)+" "+d.paint(red, "nil"))
+			return
+		}
+		d.value(depth, label, v.Elem())
+
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if v.IsNil() {
+			d.line(depth, label, d.typeName(t)+" "+d.paint(red, "nil"))
+			return
+		}
+		extra := ""
+		if v.Kind() == reflect.Chan {
+			extra = fmt.Sprintf(" len=%d cap=%d", v.Len(), v.Cap())
+		}
+		d.line(depth, label, fmt.Sprintf("%s %#x%s", d.typeName(t), v.Pointer(), extra))
+
+	default:
+		d.line(depth, label, d.typeName(t)+" "+d.scalar(v))
+	}
+}
+
+// enter marks a non-nil pointer, map or slice as being visited. it reports false if it already
+// is, which means v is inside itself.
+func (d *dumper) enter(v reflect.Value) (visit, bool) {
+	key := visit{uintptr(v.UnsafePointer()), v.Type(), 0}
+	if v.Kind() == reflect.Slice {
+		key.n = v.Len()
+	}
+	if d.visiting[key] {
+		return key, false
+	}
+	d.visiting[key] = true
+	return key, true
+}
+
+func (d *dumper) elements(depth int, v reflect.Value) {
+	for i := range v.Len() {
+		d.value(depth, fmt.Sprintf("[%d]", i), v.Index(i))
+	}
+}
+
+// scalar formats a basic value without calling Interface, which isn't allowed on unexported
+// fields.
+func (d *dumper) scalar(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return d.paint(green, strconv.Quote(v.String()))
+	case reflect.Bool:
+		return d.paint(yellow, strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int64:
+		return d.paint(yellow, strconv.FormatInt(v.Int(), 10))
+	case reflect.Int32:
+		// rune is int32, so show the character too when it's printable.
+		s := strconv.FormatInt(v.Int(), 10)
+		if strconv.IsPrint(rune(v.Int())) {
+			s += " " + strconv.QuoteRune(rune(v.Int()))
+		}
+		return d.paint(yellow, s)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return d.paint(yellow, strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		return d.paint(yellow, strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()))
+	case reflect.Complex64, reflect.Complex128:
+		return d.paint(yellow, strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits()))
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return d.paint(red, "nil")
+		}
+		if v.Kind() == reflect.Interface {
+			return d.scalar(v.Elem())
+		}
+		return fmt.Sprintf("%#x", v.Pointer())
+	case reflect.Array:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = d.scalar(v.Index(i))
+		}
+		return "[" + strings.Join(parts, " ") + "]"
+	case reflect.Struct:
+		parts := make([]string, v.NumField())
+		for i := range parts {
+			parts[i] = v.Type().Field(i).Name + ":" + d.scalar(v.Field(i))
+		}
+		return "{" + strings.Join(parts, " ") + "}"
+	case reflect.Chan:
+		return fmt.Sprintf("%#x", v.Pointer())
+	}
+	return v.Type().String()
+}
+
+func emptyMark(n int) string {
+	if n == 0 {
+		return " (empty, not nil)"
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package dump
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSdump(t *testing.T) {
+	type point struct {
+		X, y int
+	}
+	got := Sdump(map[string]any{
+		"b":     []int(nil),
+		"a":     []int{},
+		"point": point{1, 2},
+	}, Options{})
+	want := `map[string]interface {} len=3
+  "a": []int len=0 cap=0 (empty, not nil)
+  "b": []int nil
+  "point": dump.point
+    X: int 1
+    y: int 2
+`
+	if got != want {
+		t.Errorf("Sdump =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	got := Sdump([][]int{{1}}, Options{MaxDepth: 1})
+	want := "[][]int len=1 cap=1\n  [0] []int len=1 cap=1\n"
+	if got != want {
+		t.Errorf("Sdump =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCycles(t *testing.T) {
+	m := map[string]any{}
+	m["self"] = m
+
+	s := make([]any, 2)
+	s[0] = s
+	s[1] = s[:1] // shorter, so not the same slice
+
+	type node struct{ next *node }
+	n := &node{}
+	n.next = n
+
+	for name, c := range map[string]struct {
+		v     any
+		cycle int
+	}{
+		"map":     {m, 1},
+		"slice":   {s, 2},
+		"pointer": {n, 1},
+	} {
+		out := Sdump(c.v, Options{})
+		if got := strings.Count(out, "<cycle>"); got != c.cycle {
+			t.Errorf("%s: %d cycles marked, want %d:\n%s", name, got, c.cycle, out)
+		}
+	}
+}
+
+func TestSharedIsNotACycle(t *testing.T) {
+	shared := map[string]int{"x": 1}
+	out := Sdump([]map[string]int{shared, shared}, Options{})
+	if strings.Contains(out, "<cycle>") {
+		t.Errorf("a map reached twice side by side was marked as a cycle:\n%s", out)
+	}
+}
@@ -0,0 +1,131 @@
+package kvstore
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Compact rewrites the log with only the live records, dropping overwritten values and
+// tombstones. reads and writes carry on while the live records are copied; they only wait for the
+// records written in the meantime to be copied too, and for the new log to be swapped in.
+func (db *DB) Compact() error {
+	db.compactMu.Lock()
+	defer db.compactMu.Unlock()
+
+	db.mu.RLock()
+	if db.closed {
+		db.mu.RUnlock()
+		return ErrClosed
+	}
+	old, start := db.f, db.end
+	live := maps.Clone(db.index)
+	db.mu.RUnlock()
+
+	tmpPath := filepath.Join(db.dir, logName+".compact")
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	fail := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("kvstore: compacting: %w", err)
+	}
+
+	index := make(map[string]location, len(live))
+	var end int64
+	copyRecord := func(r record) error {
+		b := r.encode()
+		if _, err := tmp.WriteAt(b, end); err != nil {
+			return err
+		}
+		index[r.key] = location{end, int64(len(b))}
+		end += int64(len(b))
+		return nil
+	}
+	for _, loc := range live {
+		r, err := readRecord(old, loc.off, start)
+		if err != nil {
+			return fail(err)
+		}
+		if err := copyRecord(r); err != nil {
+			return fail(err)
+		}
+	}
+
+	// everything written since the snapshot is replayed in order, under the write lock so nothing
+	// more can arrive. a delete of a key that isn't in the new log needs no tombstone at all.
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return fail(ErrClosed)
+	}
+	var garbage int64
+	for off := start; off < db.end; {
+		r, err := readRecord(old, off, db.end)
+		if err != nil {
+			return fail(err)
+		}
+		off += r.size()
+		prev, had := index[r.key]
+		if r.kind == kindDel {
+			if had {
+				if err := copyRecord(r); err != nil {
+					return fail(err)
+				}
+				delete(index, r.key)
+				garbage += prev.size + r.size()
+			}
+			continue
+		}
+		if had {
+			garbage += prev.size
+		}
+		if err := copyRecord(r); err != nil {
+			return fail(err)
+		}
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return fail(err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(db.dir, logName)); err != nil {
+		return fail(err)
+	}
+	syncDir(db.dir)
+	old.Close()
+	db.f, db.end, db.index, db.garbage = tmp, end, index, garbage
+	return nil
+}
+
+// syncDir makes a rename in dir durable. not every platform can sync a directory, so failure is
+// ignored: the rename still happened, it just might not survive a power cut.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
+
+func (db *DB) compactLoop() {
+	defer close(db.done)
+	t := time.NewTicker(db.opts.CompactInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-db.stop:
+			return
+		case <-t.C:
+			size, garbage := db.Stats()
+			if size < 1<<20 || float64(garbage) < db.opts.CompactRatio*float64(size) {
+				continue
+			}
+			// there's no one to hand an error to. a failed compaction leaves the old log as it
+			// was, so the next tick just tries again.
+			db.Compact()
+		}
+	}
+}
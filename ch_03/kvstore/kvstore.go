@@ -0,0 +1,266 @@
+// Package kvstore keeps a map on disk, so it's still there next time the program runs.
+//
+// everything mapsInGo() puts in totalWins, m4a and delM is gone when main returns. a DB is the
+// same Put/Get/delete, but every change is appended to a log file first and the map in memory only
+// records where each key's latest value sits in that file:
+//
+//	db, err := kvstore.Open("wins", kvstore.Options{})
+//	db.Put("Laseen", []byte("7"))
+//	v, ok, err := db.Get("Laseen")
+//	db.Delete("Laseen") // appends a tombstone
+//
+// the log only ever grows at the end, so a crash can at worst leave half a record there. Open
+// finds it by its checksum and cuts it off. a bad record with good ones after it is something else,
+// a damaged disk or a stray write, and Open returns ErrCorrupt rather than cut them off too.
+// overwritten and deleted values stay in the log as
+// garbage until Compact rewrites it with only the live ones, which can also run in the background.
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	logName  = "data.log"
+	maxKey   = 1 << 16
+	maxValue = 1 << 30
+)
+
+// ErrClosed is returned by Put, Delete, Get and Compact after Close, and by Close itself the second
+// time. Len, Keys and Stats keep answering from the index as it was when the DB closed.
+var ErrClosed = errors.New("kvstore: closed")
+
+// ErrCorrupt is returned by Open when a record in the middle of the log is damaged. the log is left
+// as it is.
+var ErrCorrupt = errors.New("kvstore: log is corrupt")
+
+// Options tune a DB. the zero value is safe but slow to write: every Put waits for the disk.
+type Options struct {
+	// NoSync skips the fsync after each write. a crash can then lose the last few writes (never
+	// corrupt older ones), in exchange for much faster Puts.
+	NoSync bool
+	// CompactInterval, if positive, checks this often whether the log is worth compacting, and
+	// compacts it in the background when it is.
+	CompactInterval time.Duration
+	// CompactRatio is how much of the log has to be garbage before background compaction runs;
+	// 0 means 0.5. logs smaller than a megabyte are left alone.
+	CompactRatio float64
+}
+
+// where a key's latest value lives in the log.
+type location struct {
+	off  int64 // of the record
+	size int64 // of the whole record
+}
+
+// DB is an open store. it's safe for concurrent use.
+type DB struct {
+	dir  string
+	opts Options
+
+	mu      sync.RWMutex
+	f       *os.File
+	end     int64 // where the next record goes
+	index   map[string]location
+	garbage int64 // bytes of records that are overwritten, deleted, or tombstones
+	closed  bool
+
+	compactMu sync.Mutex // one compaction at a time
+	stop      chan struct{}
+	done      chan struct{}
+	stopOnce  sync.Once
+}
+
+// Open opens the store in dir, creating it if needed, and rebuilds the index from the log. a torn
+// record at the end of the log is truncated away; a damaged one anywhere else is ErrCorrupt.
+func Open(dir string, opts Options) (*DB, error) {
+	if opts.CompactRatio <= 0 {
+		opts.CompactRatio = 0.5
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, logName), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	// a compaction that crashed before its rename leaves this behind; the log is still whole.
+	os.Remove(filepath.Join(dir, logName+".compact"))
+
+	db := &DB{dir: dir, opts: opts, f: f, index: map[string]location{}}
+	if err := db.recover(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if opts.CompactInterval > 0 {
+		db.stop, db.done = make(chan struct{}), make(chan struct{})
+		go db.compactLoop()
+	}
+	return db, nil
+}
+
+// recover replays the log into the index, stopping at the first record that isn't whole. that's
+// only cut off if it's the tail of the log: if a whole record turns up anywhere after it, the bad
+// one wasn't the last write before a crash.
+func (db *DB) recover() error {
+	fi, err := db.f.Stat()
+	if err != nil {
+		return err
+	}
+	size := fi.Size()
+	br := &blockReader{r: db.f}
+	var off int64
+	for {
+		r, err := readRecord(br, off, size)
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, errTorn) {
+			for next := off + 1; next+headerSize <= size; next++ {
+				if _, err := readRecord(br, next, size); err == nil {
+					return fmt.Errorf("%w: bad record at offset %d, but there's a good one at %d", ErrCorrupt, off, next)
+				}
+			}
+			if err := db.f.Truncate(off); err != nil {
+				return fmt.Errorf("kvstore: truncating torn write at offset %d: %w", off, err)
+			}
+			if err := db.f.Sync(); err != nil {
+				return err
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		db.apply(r, location{off, r.size()})
+		off += r.size()
+	}
+	db.end = off
+	return nil
+}
+
+// apply updates the index and the garbage count for a record at loc.
+func (db *DB) apply(r record, loc location) {
+	if old, ok := db.index[r.key]; ok {
+		db.garbage += old.size
+	}
+	if r.kind == kindDel {
+		delete(db.index, r.key)
+		db.garbage += loc.size
+		return
+	}
+	db.index[r.key] = loc
+}
+
+func (db *DB) append(r record) error {
+	if len(r.key) > maxKey || len(r.value) > maxValue {
+		return fmt.Errorf("kvstore: key or value too large (%d, %d bytes)", len(r.key), len(r.value))
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return ErrClosed
+	}
+	b := r.encode()
+	if _, err := db.f.WriteAt(b, db.end); err != nil {
+		// a short write leaves a torn record; cut it off so the next one doesn't land after it.
+		db.f.Truncate(db.end)
+		return err
+	}
+	if !db.opts.NoSync {
+		if err := db.f.Sync(); err != nil {
+			return err
+		}
+	}
+	db.apply(r, location{db.end, int64(len(b))})
+	db.end += int64(len(b))
+	return nil
+}
+
+// Put stores value under key.
+func (db *DB) Put(key string, value []byte) error {
+	return db.append(record{kind: kindPut, key: key, value: value})
+}
+
+// Delete removes key. deleting a key that isn't there does nothing.
+func (db *DB) Delete(key string) error {
+	db.mu.RLock()
+	closed := db.closed
+	_, ok := db.index[key]
+	db.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+	if !ok {
+		return nil
+	}
+	return db.append(record{kind: kindDel, key: key})
+}
+
+// Get returns the value stored under key, and whether there is one.
+func (db *DB) Get(key string) ([]byte, bool, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return nil, false, ErrClosed
+	}
+	loc, ok := db.index[key]
+	if !ok {
+		return nil, false, nil
+	}
+	r, err := readRecord(db.f, loc.off, db.end)
+	if err != nil {
+		return nil, false, fmt.Errorf("kvstore: reading %q: %w", key, err)
+	}
+	return r.value, true, nil
+}
+
+// Len is the number of keys.
+func (db *DB) Len() int {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return len(db.index)
+}
+
+// Keys returns every key, in no particular order.
+func (db *DB) Keys() []string {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	keys := make([]string, 0, len(db.index))
+	for k := range db.index {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Stats reports the log's size and how much of it is garbage.
+func (db *DB) Stats() (size, garbage int64) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.end, db.garbage
+}
+
+// Close stops background compaction, waiting for one in progress, and closes the log. closing
+// twice, even at the same time, returns ErrClosed the second time.
+func (db *DB) Close() error {
+	db.stopOnce.Do(func() {
+		if db.stop != nil {
+			close(db.stop)
+			<-db.done
+		}
+	})
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return ErrClosed
+	}
+	db.closed = true
+	return db.f.Close()
+}
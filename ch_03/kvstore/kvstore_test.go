@@ -0,0 +1,334 @@
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// totalWins from mapsInGo(), as the store would hold it.
+var totalWins = map[string]string{"Orcas": "1", "Lions": "2", "Tigers": "3"}
+
+func open(t *testing.T, dir string) *DB {
+	t.Helper()
+	db, err := Open(dir, Options{NoSync: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func fill(t *testing.T, db *DB) {
+	t.Helper()
+	for k, v := range totalWins {
+		if err := db.Put(k, []byte(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func want(t *testing.T, db *DB, key, value string) {
+	t.Helper()
+	v, ok, err := db.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value == "" {
+		if ok {
+			t.Errorf("Get(%q) = %q, want nothing", key, v)
+		}
+		return
+	}
+	if !ok || string(v) != value {
+		t.Errorf("Get(%q) = %q, %v, want %q", key, v, ok, value)
+	}
+}
+
+func logSize(t *testing.T, dir string) int64 {
+	t.Helper()
+	fi, err := os.Stat(filepath.Join(dir, logName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fi.Size()
+}
+
+func TestPutGetDelete(t *testing.T) {
+	db := open(t, t.TempDir())
+	defer db.Close()
+	fill(t, db)
+	for k, v := range totalWins {
+		want(t, db, k, v)
+	}
+	want(t, db, "Bears", "")
+
+	db.Put("Lions", []byte("7"))
+	db.Delete("Orcas")
+	db.Delete("Bears") // not there: no tombstone
+	want(t, db, "Lions", "7")
+	want(t, db, "Orcas", "")
+	keys := db.Keys()
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"Lions", "Tigers"}) {
+		t.Errorf("Keys() = %v", keys)
+	}
+	// the first Lions, the Orcas put and its tombstone.
+	size, garbage := db.Stats()
+	wantGarbage := record{kindPut, "Lions", []byte("2")}.size() + record{kindPut, "Orcas", []byte("1")}.size() + record{kindDel, "Orcas", nil}.size()
+	if garbage != wantGarbage || size != logSize(t, db.dir) {
+		t.Errorf("Stats() = %d, %d, want %d, %d", size, garbage, logSize(t, db.dir), wantGarbage)
+	}
+}
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	db, err := Open(dir, Options{}) // synced, as the zero Options are
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(t, db)
+	db.Put("Lions", []byte("7"))
+	db.Delete("Orcas")
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db = open(t, dir)
+	defer db.Close()
+	want(t, db, "Lions", "7")
+	want(t, db, "Tigers", "3")
+	want(t, db, "Orcas", "")
+	if db.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", db.Len())
+	}
+}
+
+// TestTornTail cuts the log off at every byte of its last record, as a crash partway through
+// writing it would, and checks Open keeps the records before it and truncates the rest.
+func TestTornTail(t *testing.T) {
+	dir := t.TempDir()
+	db := open(t, dir)
+	fill(t, db)
+	whole := logSize(t, dir)
+	db.Put("Bears", []byte("a longer value, so there's more of it to tear"))
+	db.Close()
+	full, err := os.ReadFile(filepath.Join(dir, logName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for cut := whole + 1; cut < int64(len(full)); cut++ {
+		if err := os.WriteFile(filepath.Join(dir, logName), full[:cut], 0o644); err != nil {
+			t.Fatal(err)
+		}
+		db := open(t, dir)
+		want(t, db, "Bears", "")
+		for k, v := range totalWins {
+			want(t, db, k, v)
+		}
+		if got := logSize(t, dir); got != whole {
+			t.Fatalf("cut at %d: log is %d bytes after Open, want %d", cut, got, whole)
+		}
+		// the next write has to land where the torn one was, or the log would be corrupt mid-file.
+		db.Put("Bears", []byte("4"))
+		db.Close()
+		db = open(t, dir)
+		want(t, db, "Bears", "4")
+		db.Close()
+	}
+}
+
+func TestGarbageTail(t *testing.T) {
+	for name, tail := range map[string][]byte{
+		"zeros":      make([]byte, 100),
+		"bad kind":   {0, 0, 0, 0, 9, 1, 0, 0, 0, 1, 0, 0, 0, 'x', 'y'},
+		"bad crc":    record{kindPut, "Bears", []byte("4")}.encode()[1:],
+		"huge value": {0, 0, 0, 0, kindPut, 1, 0, 0, 0, 0, 0, 0, 0x40, 'x'}, // a 1 GB value, 1 byte of it
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			db := open(t, dir)
+			fill(t, db)
+			db.Close()
+			whole := logSize(t, dir)
+			f, _ := os.OpenFile(filepath.Join(dir, logName), os.O_APPEND|os.O_WRONLY, 0)
+			f.Write(tail)
+			f.Close()
+
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			db = open(t, dir)
+			runtime.ReadMemStats(&after)
+			defer db.Close()
+			if got := logSize(t, dir); got != whole {
+				t.Errorf("log is %d bytes after Open, want %d", got, whole)
+			}
+			if db.Len() != len(totalWins) {
+				t.Errorf("Len() = %d, want %d", db.Len(), len(totalWins))
+			}
+			// the header's length isn't to be trusted until the checksum is: Open shouldn't have
+			// allocated the gigabyte it asks for.
+			if n := after.TotalAlloc - before.TotalAlloc; n > 16<<20 {
+				t.Errorf("Open allocated %d bytes", n)
+			}
+		})
+	}
+}
+
+// TestCorruptMiddle damages a record with good ones after it. that isn't a torn write, and Open
+// mustn't throw the good ones away.
+func TestCorruptMiddle(t *testing.T) {
+	dir := t.TempDir()
+	db := open(t, dir)
+	db.Put("Orcas", []byte("1"))
+	db.Put("Lions", []byte("2"))
+	db.Put("Tigers", []byte("3"))
+	db.Close()
+
+	path := filepath.Join(dir, logName)
+	data, _ := os.ReadFile(path)
+	data[headerSize] ^= 0xff // the first key
+	os.WriteFile(path, data, 0o644)
+
+	if _, err := Open(dir, Options{}); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("Open = %v, want ErrCorrupt", err)
+	}
+	if got, _ := os.ReadFile(path); !slices.Equal(got, data) {
+		t.Error("Open changed a corrupt log")
+	}
+}
+
+func TestCompact(t *testing.T) {
+	dir := t.TempDir()
+	db := open(t, dir)
+	for i := range 100 {
+		db.Put("k"+strconv.Itoa(i%10), []byte(strconv.Itoa(i)))
+	}
+	db.Delete("k0")
+	if err := db.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	size, garbage := db.Stats()
+	if garbage != 0 || size != logSize(t, dir) {
+		t.Errorf("after Compact Stats() = %d, %d, log is %d bytes", size, garbage, logSize(t, dir))
+	}
+	check := func() {
+		t.Helper()
+		want(t, db, "k0", "")
+		for i := 1; i < 10; i++ {
+			want(t, db, "k"+strconv.Itoa(i), strconv.Itoa(90+i))
+		}
+	}
+	check()
+	db.Put("k1", []byte("x"))
+	db.Close()
+	if _, err := os.Stat(filepath.Join(dir, logName+".compact")); !os.IsNotExist(err) {
+		t.Errorf("compaction left its temporary file: %v", err)
+	}
+	db = open(t, dir)
+	defer db.Close()
+	want(t, db, "k1", "x")
+	want(t, db, "k2", "92")
+}
+
+// TestCompactWhileWriting runs compactions alongside writers; run it with -race.
+func TestCompactWhileWriting(t *testing.T) {
+	db := open(t, t.TempDir())
+	defer db.Close()
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Go(func() {
+			for i := range 200 {
+				key := fmt.Sprintf("w%d-%d", w, i%20)
+				if err := db.Put(key, []byte(strconv.Itoa(i))); err != nil {
+					t.Error(err)
+					return
+				}
+				if i%7 == 0 {
+					db.Delete(key)
+				}
+			}
+		})
+	}
+	wg.Go(func() {
+		for range 20 {
+			if err := db.Compact(); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+	wg.Wait()
+
+	// whatever order things happened in, the index has to agree with a replay of the log.
+	db.Compact()
+	got := map[string]string{}
+	for _, k := range db.Keys() {
+		v, _, _ := db.Get(k)
+		got[k] = string(v)
+	}
+	db.Close()
+	db = open(t, db.dir)
+	for k, v := range got {
+		want(t, db, k, v)
+	}
+	if db.Len() != len(got) {
+		t.Errorf("reopened with %d keys, want %d", db.Len(), len(got))
+	}
+}
+
+func TestClose(t *testing.T) {
+	db, err := Open(t.TempDir(), Options{NoSync: true, CompactInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(t, db)
+
+	// concurrent Closes used to both close the stop channel.
+	errs := make(chan error, 8)
+	var wg sync.WaitGroup
+	for range cap(errs) {
+		wg.Go(func() { errs <- db.Close() })
+	}
+	wg.Wait()
+	close(errs)
+	ok := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			ok++
+		case !errors.Is(err, ErrClosed):
+			t.Errorf("Close = %v", err)
+		}
+	}
+	if ok != 1 {
+		t.Errorf("%d Closes succeeded, want 1", ok)
+	}
+
+	if err := db.Put("Bears", nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("Put after Close = %v", err)
+	}
+	if err := db.Delete("no such key"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Delete of a missing key after Close = %v", err)
+	}
+	if _, _, err := db.Get("Lions"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Get after Close = %v", err)
+	}
+	if err := db.Compact(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Compact after Close = %v", err)
+	}
+}
+
+func TestTooLarge(t *testing.T) {
+	db := open(t, t.TempDir())
+	defer db.Close()
+	if err := db.Put(string(make([]byte, maxKey+1)), nil); err == nil {
+		t.Error("Put with an oversized key succeeded")
+	}
+}
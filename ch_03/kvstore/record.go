@@ -0,0 +1,120 @@
+package kvstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// a record on disk is
+//
+//	crc    uint32 // Castagnoli, over everything after it
+//	kind   byte   // put or del
+//	keyLen uint32
+//	valLen uint32
+//	key    [keyLen]byte
+//	value  [valLen]byte
+//
+// little-endian throughout. a delete is a record with no value, a tombstone.
+const headerSize = 4 + 1 + 4 + 4
+
+const (
+	kindPut byte = 1
+	kindDel byte = 2
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// errTorn means the log ends partway through a record, or a record's checksum is wrong: what a
+// crash in the middle of a write leaves behind.
+var errTorn = errors.New("kvstore: torn or corrupt record")
+
+type record struct {
+	kind  byte
+	key   string
+	value []byte
+}
+
+func (r record) size() int64 { return int64(headerSize + len(r.key) + len(r.value)) }
+
+func (r record) encode() []byte {
+	b := make([]byte, headerSize, r.size())
+	b[4] = r.kind
+	binary.LittleEndian.PutUint32(b[5:], uint32(len(r.key)))
+	binary.LittleEndian.PutUint32(b[9:], uint32(len(r.value)))
+	b = append(b, r.key...)
+	b = append(b, r.value...)
+	binary.LittleEndian.PutUint32(b, crc32.Checksum(b[4:], crcTable))
+	return b
+}
+
+// readRecord reads the record at off in a log that ends at size. it returns io.EOF at a clean end of
+// the log and errTorn for anything that isn't a whole, valid record.
+func readRecord(r io.ReaderAt, off, size int64) (record, error) {
+	var h [headerSize]byte
+	if n, err := r.ReadAt(h[:], off); err != nil {
+		if err == io.EOF && n == 0 {
+			return record{}, io.EOF
+		}
+		if err == io.EOF {
+			return record{}, errTorn
+		}
+		return record{}, err
+	}
+	kind := h[4]
+	keyLen := binary.LittleEndian.Uint32(h[5:])
+	valLen := binary.LittleEndian.Uint32(h[9:])
+	if (kind != kindPut && kind != kindDel) || keyLen > maxKey || valLen > maxValue {
+		return record{}, errTorn
+	}
+	// the lengths aren't covered by the checksum until the body is read, so a garbage header
+	// mustn't get to allocate more than the log has left.
+	if int64(keyLen)+int64(valLen) > size-off-headerSize {
+		return record{}, errTorn
+	}
+
+	body := make([]byte, keyLen+valLen)
+	if _, err := r.ReadAt(body, off+headerSize); err != nil {
+		if err == io.EOF {
+			return record{}, errTorn
+		}
+		return record{}, err
+	}
+	crc := crc32.Update(crc32.Checksum(h[4:], crcTable), crcTable, body)
+	if crc != binary.LittleEndian.Uint32(h[:4]) {
+		return record{}, errTorn
+	}
+	return record{kind: kind, key: string(body[:keyLen]), value: body[keyLen:]}, nil
+}
+
+// blockReader answers ReadAt from one cached block of the log. replaying the log reads every record
+// twice, header then body, and looking past a bad record tries every offset after it; without it
+// each of those is a system call.
+type blockReader struct {
+	r   io.ReaderAt
+	off int64 // where buf starts in the log
+	buf []byte
+}
+
+const blockSize = 1 << 20
+
+func (b *blockReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < b.off || off+int64(len(p)) > b.off+int64(len(b.buf)) {
+		n := max(len(p), blockSize)
+		if cap(b.buf) < n {
+			b.buf = make([]byte, n)
+		}
+		m, err := b.r.ReadAt(b.buf[:n], off)
+		if err != nil && err != io.EOF {
+			b.buf = b.buf[:0]
+			return 0, err
+		}
+		b.off, b.buf = off, b.buf[:m]
+	}
+	n := copy(p, b.buf[off-b.off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,362 @@
+// Package litgen writes Go source for a composite literal that rebuilds a value.
+//
+// composites.go writes its fixtures by hand: positional structs like person{"Joe", 20, "Dog"},
+// keyed ones like Employee{firstName: ..., lastName: ..., id: ...} in exerciseNo3(), and sparse
+// slices like []int{1, 5: 4, 6, 10: 100, 15}. Source does the writing from a live value instead,
+// in the style a person would: keyed fields with the zero ones left out, the sparse index form
+// when a slice is mostly zeros, map keys sorted, and everything run through gofmt. print a value
+// once and paste it into a test.
+package litgen
+
+import (
+	"fmt"
+	"go/format"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"ch_03/internal/order"
+)
+
+// Options tune the output. the zero value writes a bare expression with every named type
+// qualified by its package name.
+type Options struct {
+	// Package is the import path the source will be pasted into. types from it are written
+	// without a qualifier. a main package's path is just "main", which is what exerciseNo3's
+	// Employee needs.
+	Package string
+	// Name, when set, wraps the literal in a declaration: var Name = ....
+	Name string
+}
+
+// Source returns gofmt'd source for a literal equal to v. values that have no literal (a non-nil
+// func or channel, a pointer to a non-composite, a cycle) are errors.
+func Source(v any, opts Options) (string, error) {
+	g := &gen{opts: opts, visiting: map[uintptr]bool{}}
+	if v == nil {
+		g.b.WriteString("nil")
+	} else if err := g.value(reflect.ValueOf(v), false, false); err != nil {
+		return "", err
+	}
+
+	const prefix = "var _ = "
+	src, err := format.Source([]byte(prefix + g.b.String()))
+	if err != nil {
+		return "", fmt.Errorf("litgen: generated source doesn't parse: %w", err)
+	}
+	out := strings.TrimPrefix(string(src), prefix)
+	if opts.Name != "" {
+		out = "var " + opts.Name + " = " + out
+	}
+	return out, nil
+}
+
+type gen struct {
+	b        strings.Builder
+	opts     Options
+	visiting map[uintptr]bool
+}
+
+// value writes v. elided means the enclosing literal already names v's type (a slice element or
+// map entry), so a composite's type can be left off; typed means the context fixes the type, so a
+// constant needs no conversion.
+func (g *gen) value(v reflect.Value, elided, typed bool) error {
+	t := v.Type()
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return g.nilValue(t, typed)
+		}
+		if !elided {
+			g.b.WriteString(g.typeName(t))
+		}
+		if v.Kind() == reflect.Map {
+			return g.mapBody(v)
+		}
+		return g.sequenceBody(v)
+
+	case reflect.Array:
+		if !elided {
+			g.b.WriteString(g.typeName(t))
+		}
+		return g.sequenceBody(v)
+
+	case reflect.Struct:
+		if !elided {
+			g.b.WriteString(g.typeName(t))
+		}
+		return g.structBody(v)
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return g.nilValue(t, typed)
+		}
+		switch t.Elem().Kind() {
+		case reflect.Struct, reflect.Slice, reflect.Map, reflect.Array:
+		default:
+			return fmt.Errorf("litgen: a %s has no literal form", t)
+		}
+		if g.visiting[v.Pointer()] {
+			return fmt.Errorf("litgen: cycle through %s", t)
+		}
+		g.visiting[v.Pointer()] = true
+		defer delete(g.visiting, v.Pointer())
+		// &T{...} is written {...} where T is elided, like the values in []*Employee{{...}}.
+		if !elided {
+			g.b.WriteByte('&')
+		}
+		return g.value(v.Elem(), elided, true)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			g.b.WriteString("nil")
+			return nil
+		}
+		return g.value(v.Elem(), false, false)
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if v.IsNil() {
+			return g.nilValue(t, typed)
+		}
+		return fmt.Errorf("litgen: a non-nil %s has no literal form", t)
+	}
+	return g.basic(v, typed)
+}
+
+func (g *gen) nilValue(t reflect.Type, typed bool) error {
+	if typed {
+		g.b.WriteString("nil")
+	} else {
+		fmt.Fprintf(&g.b, "(%s)(nil)", g.typeName(t))
+	}
+	return nil
+}
+
+// basic writes a bool, number or string. where the type isn't fixed by the context it's converted
+// explicitly unless it's the type the constant would default to anyway.
+func (g *gen) basic(v reflect.Value, typed bool) error {
+	t := v.Type()
+	var (
+		lit  string
+		call bool // lit isn't a constant, so it has a type of its own
+	)
+	switch v.Kind() {
+	case reflect.Bool:
+		lit = strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		lit = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		lit = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		lit, call = floatLit(v.Float(), t.Bits())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		bits := t.Bits() / 2
+		re, reCall := floatLit(real(c), bits)
+		im, imCall := floatLit(imag(c), bits)
+		lit, call = fmt.Sprintf("complex(%s, %s)", re, im), reCall || imCall
+	case reflect.String:
+		lit = strconv.Quote(v.String())
+	default:
+		return fmt.Errorf("litgen: can't write a %s", t)
+	}
+
+	defaultType := map[reflect.Kind]bool{
+		reflect.Bool: true, reflect.Int: true, reflect.Float64: true,
+		reflect.Complex128: true, reflect.String: true,
+	}
+	needsConversion := !typed && (t.Name() != t.Kind().String() || t.PkgPath() != "" || !defaultType[t.Kind()])
+	if call {
+		// math.Inf(1) is a float64 even in a float32 field, so anything else has to convert it.
+		needsConversion = t != reflect.TypeFor[float64]() && t != reflect.TypeFor[complex128]()
+	}
+	if v.Kind() == reflect.Float64 && !typed && !needsConversion && !strings.ContainsAny(lit, ".eEI(") {
+		// 2 would default to int, 2.0 stays a float64.
+		lit += ".0"
+	}
+	if needsConversion {
+		lit = g.typeName(t) + "(" + lit + ")"
+	}
+	g.b.WriteString(lit)
+	return nil
+}
+
+// floatLit writes the shortest float that reads back the same. Inf, NaN and -0 have no literal
+// (constant -0.0 is just 0), so they're written as calls into math, and call is true.
+func floatLit(f float64, bits int) (lit string, call bool) {
+	switch {
+	case math.IsInf(f, 1):
+		return "math.Inf(1)", true
+	case math.IsInf(f, -1):
+		return "math.Inf(-1)", true
+	case math.IsNaN(f):
+		return "math.NaN()", true
+	case f == 0 && math.Signbit(f):
+		return "math.Copysign(0, -1)", true
+	}
+	return strconv.FormatFloat(f, 'g', -1, bits), false
+}
+
+func (g *gen) structBody(v reflect.Value) error {
+	t := v.Type()
+	g.b.WriteByte('{')
+	wrote := false
+	for i := range v.NumField() {
+		f := v.Field(i)
+		if isZero(f) || t.Field(i).Name == "_" {
+			continue
+		}
+		if !wrote {
+			g.b.WriteByte('\n')
+			wrote = true
+		}
+		g.b.WriteString(t.Field(i).Name + ": ")
+		if err := g.value(f, false, true); err != nil {
+			return err
+		}
+		g.b.WriteString(",\n")
+	}
+	g.b.WriteByte('}')
+	return nil
+}
+
+// sequenceBody writes the elements of a slice or array. when most of them are zero only the rest
+// are written, with their indexes, the way composites.go writes []int{1, 5: 4, 6, 10: 100, 15}.
+// a slice's last element is always written so the length comes out right.
+func (g *gen) sequenceBody(v reflect.Value) error {
+	n := v.Len()
+	zeros := 0
+	for i := range n {
+		if isZero(v.Index(i)) {
+			zeros++
+		}
+	}
+	sparse := n >= 4 && zeros*2 > n
+	if v.Kind() == reflect.Array && zeros == n {
+		// a zero array needs no elements at all: [3]int{}.
+		n = 0
+	}
+	multiline := isComposite(v.Type().Elem())
+
+	g.b.WriteByte('{')
+	if multiline && n > 0 {
+		g.b.WriteByte('\n')
+	}
+	next := 0 // the index the next element gets without a key
+	for i := range n {
+		e := v.Index(i)
+		last := i == n-1 && v.Kind() == reflect.Slice
+		if sparse && isZero(e) && !last {
+			continue
+		}
+		if i != next {
+			fmt.Fprintf(&g.b, "%d: ", i)
+		}
+		if err := g.value(e, true, true); err != nil {
+			return err
+		}
+		next = i + 1
+		switch {
+		case multiline:
+			g.b.WriteString(",\n")
+		case i < n-1:
+			g.b.WriteString(", ")
+		}
+	}
+	g.b.WriteByte('}')
+	return nil
+}
+
+func (g *gen) mapBody(v reflect.Value) error {
+	g.b.WriteByte('{')
+	if v.Len() > 0 {
+		g.b.WriteByte('\n')
+	}
+	for _, k := range order.Keys(v) {
+		if err := g.value(k, true, true); err != nil {
+			return err
+		}
+		g.b.WriteString(": ")
+		if err := g.value(v.MapIndex(k), true, true); err != nil {
+			return err
+		}
+		g.b.WriteString(",\n")
+	}
+	g.b.WriteByte('}')
+	return nil
+}
+
+// isZero is whether v can be left out of a literal. reflect counts -0 as zero, but leaving it out
+// would write 0.
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0 && !math.Signbit(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return c == 0 && !math.Signbit(real(c)) && !math.Signbit(imag(c))
+	case reflect.Array:
+		for i := range v.Len() {
+			if !isZero(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if !isZero(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return v.IsZero()
+}
+
+func isComposite(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Map, reflect.Array, reflect.Pointer:
+		return true
+	}
+	return false
+}
+
+// typeName writes t as it would be spelled in Options.Package.
+func (g *gen) typeName(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" || t.PkgPath() == g.opts.Package {
+			return t.Name()
+		}
+		return t.String()
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + g.typeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + g.typeName(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), g.typeName(t.Elem()))
+	case reflect.Map:
+		return "map[" + g.typeName(t.Key()) + "]" + g.typeName(t.Elem())
+	case reflect.Struct:
+		// an anonymous struct, like city in structs().
+		fields := make([]string, t.NumField())
+		for i := range fields {
+			f := t.Field(i)
+			fields[i] = f.Name + " " + g.typeName(f.Type)
+			if f.Anonymous {
+				fields[i] = g.typeName(f.Type)
+			}
+			if f.Tag != "" {
+				fields[i] += " " + strconv.Quote(string(f.Tag))
+			}
+		}
+		return "struct{ " + strings.Join(fields, "; ") + " }"
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "any"
+		}
+	}
+	return t.String()
+}
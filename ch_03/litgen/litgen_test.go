@@ -0,0 +1,115 @@
+package litgen
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math"
+	"strings"
+	"testing"
+)
+
+type Employee struct {
+	firstName string
+	lastName  string
+	id        int
+}
+
+type reading struct {
+	F32  float32
+	F64  float64
+	C64  complex64
+	Temp celsius
+	All  []float32
+}
+
+type celsius float64
+
+func TestSource(t *testing.T) {
+	for _, c := range []struct {
+		v    any
+		opts Options
+		want string
+	}{
+		{Employee{"Ada", "Igwe", 0}, Options{Package: "ch_03/litgen"}, "Employee{\n\tfirstName: \"Ada\",\n\tlastName:  \"Igwe\",\n}"},
+		{[]int{1, 0, 0, 0, 0, 4, 6}, Options{}, "[]int{1, 5: 4, 6}"},
+		{map[string]int{"b": 2, "a": 1}, Options{Name: "m"}, "var m = map[string]int{\n\t\"a\": 1,\n\t\"b\": 2,\n}"},
+		{2.0, Options{}, "2.0"},
+		{float32(1.5), Options{}, "float32(1.5)"},
+		{math.Inf(1), Options{}, "math.Inf(1)"},
+		{math.Copysign(0, -1), Options{}, "math.Copysign(0, -1)"},
+		{float32(math.Inf(-1)), Options{}, "float32(math.Inf(-1))"},
+		{[]float32{float32(math.NaN())}, Options{}, "[]float32{float32(math.NaN())}"},
+		{[]celsius{celsius(math.Inf(1))}, Options{Package: "ch_03/litgen"}, "[]celsius{celsius(math.Inf(1))}"},
+		{[]float64{math.Copysign(0, -1)}, Options{}, "[]float64{math.Copysign(0, -1)}"},
+		{[4]float64{3: math.Copysign(0, -1)}, Options{}, "[4]float64{3: math.Copysign(0, -1)}"},
+	} {
+		got, err := Source(c.v, c.opts)
+		if err != nil {
+			t.Errorf("Source(%#v): %v", c.v, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("Source(%#v) =\n%s\nwant\n%s", c.v, got, c.want)
+		}
+	}
+}
+
+// TestSpecialFloatsCompile type-checks the literal for a struct full of values that have no
+// constant form.
+func TestSpecialFloatsCompile(t *testing.T) {
+	v := reading{
+		F32:  float32(math.Inf(1)),
+		F64:  math.NaN(),
+		C64:  complex(float32(math.Inf(-1)), float32(math.Copysign(0, -1))),
+		Temp: celsius(math.Copysign(0, -1)),
+		All:  []float32{1, float32(math.NaN()), float32(math.Copysign(0, -1))},
+	}
+	src, err := Source(v, Options{Package: "ch_03/litgen", Name: "v"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"F32:  float32(math.Inf(1))", "Temp: celsius(math.Copysign(0, -1))", "float32(math.Copysign(0, -1))"} {
+		if !strings.Contains(src, want) {
+			t.Errorf("source doesn't contain %q:\n%s", want, src)
+		}
+	}
+
+	file := `package litgen
+
+import "math"
+
+type reading struct {
+	F32  float32
+	F64  float64
+	C64  complex64
+	Temp celsius
+	All  []float32
+}
+
+type celsius float64
+
+` + src
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "gen.go", file, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("ch_03/litgen", fset, []*ast.File{f}, nil); err != nil {
+		t.Errorf("generated source doesn't compile: %v\n%s", err, src)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	type node struct{ next *node }
+	n := &node{}
+	n.next = n
+	for _, v := range []any{n, func() {}, make(chan int), new(int)} {
+		if _, err := Source(v, Options{}); err == nil {
+			t.Errorf("Source(%T) succeeded", v)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Package orderedmap is a map that remembers the order keys went in.
+//
+// mapsInGo() prints maps and blocks() in ch_04 ranges over uniqueNames, and both come out in a
+// different order every run because Go randomises map iteration on purpose. OrderedMap keeps a
+// linked list next to the map, so Get, Set and Delete stay O(1) but ranging over it, or encoding
+// it as JSON, always goes in insertion order.
+package orderedmap
+
+import "iter"
+
+type entry[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next *entry[K, V]
+}
+
+// OrderedMap is a map from K to V that iterates in insertion order. the zero value is an empty
+// map ready to use. it isn't safe for concurrent use.
+//
+// like a map, an OrderedMap that has been used is a reference to its entries: a copy (a struct
+// holding one by value, passed around) sees the same entries as the original.
+type OrderedMap[K comparable, V any] struct {
+	index map[K]*entry[K, V]
+	// root is a sentinel: root.next is the oldest entry and root.prev the newest, so the list is a
+	// ring and inserting or unlinking never has to check for nil. it's a pointer so that copies of
+	// the OrderedMap all point at the same ring.
+	root *entry[K, V]
+}
+
+// New returns an empty map.
+func New[K comparable, V any]() *OrderedMap[K, V] {
+	return new(OrderedMap[K, V]).lazyInit()
+}
+
+func (m *OrderedMap[K, V]) lazyInit() *OrderedMap[K, V] {
+	if m.index == nil {
+		m.index = map[K]*entry[K, V]{}
+		m.root = &entry[K, V]{}
+		m.root.next, m.root.prev = m.root, m.root
+	}
+	return m
+}
+
+// Len is the number of entries.
+func (m *OrderedMap[K, V]) Len() int { return len(m.index) }
+
+// Get returns the value for k, and whether it was there.
+func (m *OrderedMap[K, V]) Get(k K) (V, bool) {
+	if e, ok := m.index[k]; ok {
+		return e.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Has reports whether k is in the map.
+func (m *OrderedMap[K, V]) Has(k K) bool {
+	_, ok := m.index[k]
+	return ok
+}
+
+// Set stores v under k. a new key goes at the back; an existing key keeps its place, the way
+// assigning to a key that's already in a map doesn't make it newer. it reports whether k was new.
+func (m *OrderedMap[K, V]) Set(k K, v V) bool {
+	m.lazyInit()
+	if e, ok := m.index[k]; ok {
+		e.value = v
+		return false
+	}
+	e := &entry[K, V]{key: k, value: v}
+	m.index[k] = e
+	m.insertAfter(e, m.root.prev)
+	return true
+}
+
+// Delete removes k and reports whether it was there.
+func (m *OrderedMap[K, V]) Delete(k K) bool {
+	e, ok := m.index[k]
+	if !ok {
+		return false
+	}
+	delete(m.index, k)
+	unlink(e)
+	return true
+}
+
+// Clear removes every entry.
+func (m *OrderedMap[K, V]) Clear() {
+	if m.index == nil {
+		return
+	}
+	clear(m.index)
+	m.root.next, m.root.prev = m.root, m.root
+}
+
+// MoveToFront makes k the oldest entry. it reports whether k was there.
+func (m *OrderedMap[K, V]) MoveToFront(k K) bool {
+	e, ok := m.index[k]
+	if !ok {
+		return false
+	}
+	unlink(e)
+	m.insertAfter(e, m.root)
+	return true
+}
+
+// MoveToBack makes k the newest entry. it reports whether k was there.
+func (m *OrderedMap[K, V]) MoveToBack(k K) bool {
+	e, ok := m.index[k]
+	if !ok {
+		return false
+	}
+	unlink(e)
+	m.insertAfter(e, m.root.prev)
+	return true
+}
+
+// Front returns the oldest entry; ok is false if the map is empty.
+func (m *OrderedMap[K, V]) Front() (k K, v V, ok bool) {
+	if m.Len() == 0 {
+		return k, v, false
+	}
+	return m.root.next.key, m.root.next.value, true
+}
+
+// Back returns the newest entry; ok is false if the map is empty.
+func (m *OrderedMap[K, V]) Back() (k K, v V, ok bool) {
+	if m.Len() == 0 {
+		return k, v, false
+	}
+	return m.root.prev.key, m.root.prev.value, true
+}
+
+func (m *OrderedMap[K, V]) insertAfter(e, at *entry[K, V]) {
+	e.prev, e.next = at, at.next
+	at.next.prev = e
+	at.next = e
+}
+
+func unlink[K comparable, V any](e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+}
+
+// All iterates from oldest to newest. like ranging over a plain map, entries deleted during the
+// loop aren't visited if it hasn't reached them yet, and entries added may or may not be.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.Len() == 0 {
+			return
+		}
+		for e := m.root.next; e != m.root; {
+			next := e.next
+			if m.index[e.key] == e && !yield(e.key, e.value) {
+				return
+			}
+			e = next
+		}
+	}
+}
+
+// Backward iterates from newest to oldest.
+func (m *OrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.Len() == 0 {
+			return
+		}
+		for e := m.root.prev; e != m.root; {
+			prev := e.prev
+			if m.index[e.key] == e && !yield(e.key, e.value) {
+				return
+			}
+			e = prev
+		}
+	}
+}
+
+// Keys iterates over the keys in order.
+func (m *OrderedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values iterates over the values in key order.
+func (m *OrderedMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Collect builds a map from key/value pairs, in the order they come, like maps.Collect.
+func Collect[K comparable, V any](seq iter.Seq2[K, V]) *OrderedMap[K, V] {
+	m := New[K, V]()
+	for k, v := range seq {
+		m.Set(k, v)
+	}
+	return m
+}
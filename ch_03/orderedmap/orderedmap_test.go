@@ -0,0 +1,121 @@
+package orderedmap
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func keys[K comparable, V any](m *OrderedMap[K, V]) []K { return slices.Collect(m.Keys()) }
+
+func TestOrder(t *testing.T) {
+	var m OrderedMap[string, int] // the zero value works
+	for i, k := range []string{"Laseen", "Kalam", "Apsalar", "Kalam"} {
+		m.Set(k, i)
+	}
+	if got, want := keys(&m), []string{"Laseen", "Kalam", "Apsalar"}; !slices.Equal(got, want) {
+		t.Errorf("keys = %v, want %v", got, want)
+	}
+	if v, _ := m.Get("Kalam"); v != 3 {
+		t.Errorf("Kalam = %d, want 3", v)
+	}
+
+	m.MoveToFront("Apsalar")
+	m.MoveToBack("Laseen")
+	m.Delete("Kalam")
+	if got, want := keys(&m), []string{"Apsalar", "Laseen"}; !slices.Equal(got, want) {
+		t.Errorf("keys = %v, want %v", got, want)
+	}
+	var back []string
+	for k := range m.Backward() {
+		back = append(back, k)
+	}
+	if want := []string{"Laseen", "Apsalar"}; !slices.Equal(back, want) {
+		t.Errorf("Backward gave %v, want %v", back, want)
+	}
+	if k, _, _ := m.Back(); k != "Laseen" {
+		t.Errorf("Back = %q", k)
+	}
+
+	m.Clear()
+	if m.Len() != 0 || len(keys(&m)) != 0 {
+		t.Errorf("Clear left %v", keys(&m))
+	}
+	m.Set("again", 1)
+	if got := keys(&m); !slices.Equal(got, []string{"again"}) {
+		t.Errorf("after Clear, keys = %v", got)
+	}
+}
+
+func TestDeleteWhileRanging(t *testing.T) {
+	m := Collect(maps.All(map[int]bool{1: true}))
+	for i := 2; i <= 5; i++ {
+		m.Set(i, true)
+	}
+	var seen []int
+	for k := range m.All() {
+		seen = append(seen, k)
+		if k == 2 {
+			m.Delete(3)
+		}
+	}
+	if want := []int{1, 2, 4, 5}; !slices.Equal(seen, want) {
+		t.Errorf("visited %v, want %v", seen, want)
+	}
+}
+
+// a copy shares the entries, the way copying a map does.
+func TestCopy(t *testing.T) {
+	m := New[string, int]()
+	m.Set("a", 1)
+	c := *m
+	c.Set("b", 2)
+	m.Delete("a")
+	if got := keys(m); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("original keys = %v, want [b]", got)
+	}
+	if got := keys(&c); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("copy keys = %v, want [b]", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type doc struct {
+		Wins OrderedMap[string, int] `json:"wins"`
+	}
+	var d doc
+	d.Wins.Set("z", 1)
+	d.Wins.Set("a", 2)
+
+	// marshaled by value, so MarshalJSON can't need a pointer.
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"wins":{"z":1,"a":2}}`; string(b) != want {
+		t.Errorf("Marshal = %s, want %s", b, want)
+	}
+
+	var back doc
+	if err := json.Unmarshal([]byte(`{"wins":{"b":1,"a":2,"b":3}}`), &back); err != nil {
+		t.Fatal(err)
+	}
+	if got := keys(&back.Wins); !slices.Equal(got, []string{"b", "a"}) {
+		t.Errorf("Unmarshal keys = %v", got)
+	}
+	if v, _ := back.Wins.Get("b"); v != 3 {
+		t.Errorf("b = %d, want the last value 3", v)
+	}
+
+	ints := New[int, string]()
+	ints.Set(10, "x")
+	ints.Set(-2, "y")
+	b, err = json.Marshal(ints)
+	if err != nil || string(b) != `{"10":"x","-2":"y"}` {
+		t.Errorf("Marshal int keys = %s, %v", b, err)
+	}
+	if err := json.Unmarshal([]byte(`{"nope":"x"}`), ints); err == nil {
+		t.Error("Unmarshal accepted a non-integer key for an int map")
+	}
+}
@@ -0,0 +1,114 @@
+// Package safemap is a map whose zero value can be written to.
+//
+// mapsInGo() declares var nilMap map[string]int and warns that nilMap["x"] = 1 panics, so every
+// struct with a map field needs a constructor that remembers to make it. a Map makes itself on the
+// first write instead:
+//
+//	type scoreboard struct {
+//		totalWins safemap.Map[string, int]
+//	}
+//
+//	var s scoreboard
+//	s.totalWins.Set("Laseen", 1) // no make, no panic
+//
+// reads on a zero Map behave like reads on a nil map: nothing is there and nothing is allocated.
+// unlike a plain map, a Map can be shared between goroutines.
+package safemap
+
+import (
+	"iter"
+	"maps"
+	"sync"
+)
+
+// Map is a map from K to V whose zero value is empty and ready to use. it's safe for concurrent
+// use, and like anything holding a mutex it must not be copied after first use.
+type Map[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+// FromMap returns a Map holding a copy of m.
+func FromMap[K comparable, V any](m map[K]V) Map[K, V] {
+	return Map[K, V]{m: maps.Clone(m)}
+}
+
+// Get returns the value for k, or V's zero value if it isn't there, like m[k].
+func (m *Map[K, V]) Get(k K) V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.m[k]
+}
+
+// GetOK is the comma-ok form, v, ok := m[k].
+func (m *Map[K, V]) GetOK(k K) (V, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.m[k]
+	return v, ok
+}
+
+// Set stores v under k, making the map first if it's still the zero value.
+func (m *Map[K, V]) Set(k K, v V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.m == nil {
+		m.m = map[K]V{}
+	}
+	m.m[k] = v
+}
+
+// Delete removes k. like delete on a nil map, it does nothing on a zero Map.
+func (m *Map[K, V]) Delete(k K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.m, k)
+}
+
+// Clear removes every entry but keeps the allocated map for reuse.
+func (m *Map[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.m)
+}
+
+// Len is the number of entries.
+func (m *Map[K, V]) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.m)
+}
+
+// All iterates over the entries, in the same random order ranging over a map does. it ranges over
+// a copy taken when the loop starts, so the loop body can call Set or Delete without deadlocking.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m.ToMap() {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Keys iterates over the keys, from a copy like All.
+func (m *Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// ToMap returns a copy of the entries as a plain map. it's never nil, so the caller can write to
+// it straight away.
+func (m *Map[K, V]) ToMap() map[K]V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.m == nil {
+		return map[K]V{}
+	}
+	return maps.Clone(m.m)
+}
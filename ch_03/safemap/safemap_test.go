@@ -0,0 +1,138 @@
+package safemap
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// totalWins from mapsInGo().
+var totalWins = map[string]int{"Orcas": 1, "Lions": 2, "Tigers": 3}
+
+func TestZeroValue(t *testing.T) {
+	var m Map[string, int]
+	if v := m.Get("Lions"); v != 0 {
+		t.Errorf("Get on a zero Map = %d", v)
+	}
+	if v, ok := m.GetOK("Lions"); v != 0 || ok {
+		t.Errorf("GetOK on a zero Map = %d, %v", v, ok)
+	}
+	m.Delete("Lions")
+	m.Clear()
+	if m.Len() != 0 || m.m != nil {
+		t.Errorf("reads, Delete and Clear allocated on a zero Map: %v", m.m)
+	}
+	for k := range m.All() {
+		t.Errorf("zero Map has key %q", k)
+	}
+	if got := m.ToMap(); got == nil || len(got) != 0 {
+		t.Errorf("ToMap of a zero Map = %#v, want an empty non-nil map", got)
+	}
+
+	m.Set("Lions", 2) // where nilMap["x"] = 1 panics
+	if v, ok := m.GetOK("Lions"); v != 2 || !ok {
+		t.Errorf("GetOK after Set = %d, %v", v, ok)
+	}
+}
+
+func TestInStruct(t *testing.T) {
+	type scoreboard struct {
+		totalWins Map[string, int]
+	}
+	var s scoreboard
+	for k, v := range totalWins {
+		s.totalWins.Set(k, v)
+	}
+	if s.totalWins.Len() != 3 || s.totalWins.Get("Tigers") != 3 {
+		t.Errorf("scoreboard = %v", s.totalWins.ToMap())
+	}
+}
+
+func TestSetGetDelete(t *testing.T) {
+	m := FromMap(totalWins)
+	m.Set("Lions", 7)
+	m.Delete("Orcas")
+	m.Delete("Bears")
+	if v, ok := m.GetOK("Orcas"); ok {
+		t.Errorf("GetOK after Delete = %d, true", v)
+	}
+	if want := map[string]int{"Lions": 7, "Tigers": 3}; !maps.Equal(m.ToMap(), want) {
+		t.Errorf("ToMap = %v, want %v", m.ToMap(), want)
+	}
+	keys := slices.Sorted(m.Keys())
+	if !slices.Equal(keys, []string{"Lions", "Tigers"}) {
+		t.Errorf("Keys = %v", keys)
+	}
+
+	m.Clear()
+	if m.Len() != 0 {
+		t.Errorf("Len after Clear = %d", m.Len())
+	}
+	m.Set("Bears", 1)
+	if m.Get("Bears") != 1 {
+		t.Error("Set after Clear lost the value")
+	}
+}
+
+func TestCopies(t *testing.T) {
+	src := maps.Clone(totalWins)
+	m := FromMap(src)
+	src["Lions"] = 100
+	if m.Get("Lions") != 2 {
+		t.Errorf("changing FromMap's argument reached the Map: Lions = %d", m.Get("Lions"))
+	}
+
+	out := m.ToMap()
+	out["Lions"] = 100
+	delete(out, "Orcas")
+	if m.Get("Lions") != 2 || m.Len() != 3 {
+		t.Errorf("changing ToMap's result reached the Map: %v", m.ToMap())
+	}
+}
+
+func TestChangeWhileRanging(t *testing.T) {
+	m := FromMap(totalWins)
+	n := 0
+	for k := range m.All() {
+		m.Delete(k)
+		m.Set(k+"!", 0)
+		n++
+	}
+	if n != 3 || m.Len() != 3 || m.Get("Lions!") != 0 {
+		t.Errorf("after %d iterations: %v", n, m.ToMap())
+	}
+}
+
+// TestConcurrent is for -race: writers, readers and ranging all at once on one zero Map.
+func TestConcurrent(t *testing.T) {
+	var m Map[string, int]
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Go(func() {
+			for i := range 500 {
+				k := strconv.Itoa(i % 50)
+				m.Set(k, w*1000+i)
+				if i%10 == 0 {
+					m.Delete(k)
+				}
+			}
+		})
+		wg.Go(func() {
+			for i := range 500 {
+				m.Get(strconv.Itoa(i % 50))
+				m.GetOK(strconv.Itoa(i % 50))
+				if i%50 == 0 {
+					for range m.All() {
+					}
+					m.Len()
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if m.Len() > 50 {
+		t.Errorf("Len = %d, want at most 50", m.Len())
+	}
+}
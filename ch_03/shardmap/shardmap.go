@@ -0,0 +1,205 @@
+// Package shardmap is a map that many goroutines can share.
+//
+// totalWins in mapsInGo() is a plain map, and two goroutines doing totalWins["Laseen"]++ at once
+// is a data race (the runtime may even stop the program with "concurrent map writes"). one mutex
+// around the map fixes that but makes every goroutine queue for it. Map splits the keys across
+// shards, each with its own lock, so goroutines working on different keys rarely wait for each
+// other:
+//
+//	wins := shardmap.New[string, int](0)
+//	wins.Update("Laseen", func(n int, _ bool) int { return n + 1 }) // safe from any goroutine
+//
+// every method that reads and then writes (Update, Compute, GetOrSet) does both under one lock,
+// so nothing can happen in between.
+package shardmap
+
+import (
+	"hash/maphash"
+	"iter"
+	"maps"
+	"runtime"
+	"sync"
+)
+
+type shard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+// Map is a concurrency-safe map from K to V. the zero value is an empty map ready to use, with
+// the default number of shards; New picks the number. a Map must not be copied after first use.
+type Map[K comparable, V any] struct {
+	once   sync.Once
+	seed   maphash.Seed
+	shards []shard[K, V]
+}
+
+// New returns an empty map split into n shards. n <= 0 picks a default based on GOMAXPROCS,
+// which is plenty unless goroutines hammer a handful of keys.
+func New[K comparable, V any](n int) *Map[K, V] {
+	m := new(Map[K, V])
+	m.once.Do(func() { m.init(n) })
+	return m
+}
+
+// lazyInit sets up a zero Map the first time it's used, from whichever goroutine gets there first.
+func (m *Map[K, V]) lazyInit() {
+	m.once.Do(func() { m.init(0) })
+}
+
+func (m *Map[K, V]) init(n int) {
+	if n <= 0 {
+		n = 4 * runtime.GOMAXPROCS(0)
+	}
+	m.seed, m.shards = maphash.MakeSeed(), make([]shard[K, V], n)
+	for i := range m.shards {
+		m.shards[i].m = map[K]V{}
+	}
+}
+
+func (m *Map[K, V]) shard(k K) *shard[K, V] {
+	m.lazyInit()
+	return &m.shards[maphash.Comparable(m.seed, k)%uint64(len(m.shards))]
+}
+
+// Get returns the value for k, and whether it was there.
+func (m *Map[K, V]) Get(k K) (V, bool) {
+	s := m.shard(k)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.m[k]
+	return v, ok
+}
+
+// Set stores v under k.
+func (m *Map[K, V]) Set(k K, v V) {
+	s := m.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[k] = v
+}
+
+// Delete removes k and reports whether it was there.
+func (m *Map[K, V]) Delete(k K) bool {
+	s := m.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.m[k]
+	delete(s.m, k)
+	return ok
+}
+
+// GetOrSet returns the value already stored under k if there is one (loaded is true), and
+// otherwise stores v and returns it.
+func (m *Map[K, V]) GetOrSet(k K, v V) (actual V, loaded bool) {
+	s := m.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if old, ok := s.m[k]; ok {
+		return old, true
+	}
+	s.m[k] = v
+	return v, false
+}
+
+// Update replaces the value under k with fn(old, exists) and returns it. old is the zero value
+// when k isn't there, so counting is just returning n + 1.
+//
+// fn runs with k's shard locked: it must not call back into the map.
+func (m *Map[K, V]) Update(k K, fn func(old V, exists bool) V) V {
+	s := m.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.m[k]
+	v := fn(old, ok)
+	s.m[k] = v
+	return v
+}
+
+// Compute is Update that can also delete: when fn returns keep == false, k is removed (or never
+// added). it returns what's stored under k afterwards.
+//
+// fn runs with k's shard locked: it must not call back into the map.
+func (m *Map[K, V]) Compute(k K, fn func(old V, exists bool) (v V, keep bool)) (V, bool) {
+	s := m.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.m[k]
+	v, keep := fn(old, ok)
+	if !keep {
+		delete(s.m, k)
+		var zero V
+		return zero, false
+	}
+	s.m[k] = v
+	return v, true
+}
+
+// Len is the number of entries. other goroutines can change it before it's returned, so it's only
+// exact when nothing else is writing.
+func (m *Map[K, V]) Len() int {
+	m.lazyInit()
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		n += len(s.m)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Clear removes every entry.
+func (m *Map[K, V]) Clear() {
+	m.lazyInit()
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.Lock()
+		clear(s.m)
+		s.mu.Unlock()
+	}
+}
+
+// All iterates over a copy of each shard in turn, so the loop body can do anything, including
+// writing to the map. every shard is a consistent snapshot, but the shards are copied at different
+// moments: for one snapshot of the whole map, use Snapshot.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.lazyInit()
+		for i := range m.shards {
+			s := &m.shards[i]
+			s.mu.RLock()
+			cp := maps.Clone(s.m)
+			s.mu.RUnlock()
+			for k, v := range cp {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Snapshot copies the whole map into a plain one. it holds every shard's read lock at once, so
+// the copy is the map as it was at a single moment; writers wait until it's done.
+func (m *Map[K, V]) Snapshot() map[K]V {
+	m.lazyInit()
+	for i := range m.shards {
+		m.shards[i].mu.RLock()
+	}
+	defer func() {
+		for i := range m.shards {
+			m.shards[i].mu.RUnlock()
+		}
+	}()
+
+	n := 0
+	for i := range m.shards {
+		n += len(m.shards[i].m)
+	}
+	out := make(map[K]V, n)
+	for i := range m.shards {
+		maps.Copy(out, m.shards[i].m)
+	}
+	return out
+}
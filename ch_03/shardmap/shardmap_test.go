@@ -0,0 +1,237 @@
+package shardmap
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// these tests are most useful under go test -race.
+
+func TestUpdateConcurrent(t *testing.T) {
+	wins := New[string, int](0)
+	players := []string{"Laseen", "Kalam", "Apsalar"}
+	var wg sync.WaitGroup
+	for g := range 16 {
+		wg.Go(func() {
+			for i := range 1000 {
+				wins.Update(players[(g+i)%len(players)], func(n int, _ bool) int { return n + 1 })
+			}
+		})
+	}
+	wg.Wait()
+
+	total := 0
+	for _, n := range wins.All() {
+		total += n
+	}
+	if total != 16*1000 || wins.Len() != len(players) {
+		t.Errorf("total = %d over %d keys, want %d over %d", total, wins.Len(), 16*1000, len(players))
+	}
+}
+
+func TestGetOrSetConcurrent(t *testing.T) {
+	m := New[int, int](4)
+	var stored atomic.Int64
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Go(func() {
+			for k := range 500 {
+				if _, loaded := m.GetOrSet(k, g); !loaded {
+					stored.Add(1)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if stored.Load() != 500 {
+		t.Errorf("%d GetOrSet calls stored a value, want exactly one per key (500)", stored.Load())
+	}
+}
+
+func TestCompute(t *testing.T) {
+	m := New[string, int](2)
+	m.Set("a", 1)
+	if v, ok := m.Compute("a", func(old int, _ bool) (int, bool) { return old + 1, true }); v != 2 || !ok {
+		t.Errorf("Compute increment = %d, %v", v, ok)
+	}
+	if _, ok := m.Compute("a", func(int, bool) (int, bool) { return 0, false }); ok {
+		t.Error("Compute with keep=false kept the key")
+	}
+	if _, ok := m.Get("a"); ok {
+		t.Error("a still there after Compute deleted it")
+	}
+	if _, ok := m.Compute("b", func(_ int, exists bool) (int, bool) { return 1, exists }); ok {
+		t.Error("Compute added a key it was told not to keep")
+	}
+	if m.Delete("b") {
+		t.Error("Delete found b")
+	}
+}
+
+// the zero Map sets itself up on first use, even when that's many goroutines at once.
+func TestZeroValue(t *testing.T) {
+	var m Map[int, string]
+	if m.Len() != 0 || len(m.Snapshot()) != 0 {
+		t.Error("zero Map isn't empty")
+	}
+
+	var z Map[int, int]
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Go(func() { z.Set(g, g) })
+	}
+	wg.Wait()
+	if z.Len() != 8 {
+		t.Errorf("Len = %d, want 8", z.Len())
+	}
+	z.Clear()
+	if z.Len() != 0 {
+		t.Errorf("Len after Clear = %d", z.Len())
+	}
+}
+
+func TestSnapshotWhileWriting(t *testing.T) {
+	m := New[int, int](0)
+	for i := range 100 {
+		m.Set(i, i)
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+				m.Set(i%100, i)
+			}
+		}
+	})
+	for range 50 {
+		snap := m.Snapshot()
+		if len(snap) != 100 {
+			t.Fatalf("snapshot has %d keys", len(snap))
+		}
+		// All lets the loop body write to the map.
+		for k := range m.All() {
+			m.Delete(k + 1000)
+		}
+	}
+	close(done)
+	wg.Wait()
+	if got := maps.Collect(m.All()); len(got) != 100 {
+		t.Errorf("All found %d keys, want 100", len(got))
+	}
+}
+
+// the benchmarks compare Map with sync.Map and a single RWMutex around a plain map, on a mix of
+// reads and writes over 1000 keys from every P at once.
+
+type store interface {
+	Get(k string) (int, bool)
+	Set(k string, v int)
+}
+
+type rwMap struct {
+	mu sync.RWMutex
+	m  map[string]int
+}
+
+func (r *rwMap) Get(k string) (int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	v, ok := r.m[k]
+	return v, ok
+}
+
+func (r *rwMap) Set(k string, v int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.m[k] = v
+}
+
+type syncMap struct{ m sync.Map }
+
+func (s *syncMap) Get(k string) (int, bool) {
+	v, ok := s.m.Load(k)
+	if !ok {
+		return 0, false
+	}
+	return v.(int), true
+}
+
+func (s *syncMap) Set(k string, v int) { s.m.Store(k, v) }
+
+var benchKeys = func() []string {
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = "player" + strconv.Itoa(i)
+	}
+	return keys
+}()
+
+func benchMixed(b *testing.B, s store, writePercent int) {
+	for i, k := range benchKeys {
+		s.Set(k, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			k := benchKeys[i%len(benchKeys)]
+			if i%100 < writePercent {
+				s.Set(k, i)
+			} else {
+				s.Get(k)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkMixed(b *testing.B) {
+	for _, writes := range []int{1, 10, 50} {
+		for _, impl := range []struct {
+			name string
+			new  func() store
+		}{
+			{"shardmap", func() store { return New[string, int](0) }},
+			{"sync.Map", func() store { return &syncMap{} }},
+			{"RWMutex", func() store { return &rwMap{m: map[string]int{}} }},
+		} {
+			b.Run(fmt.Sprintf("writes=%d%%/%s", writes, impl.name), func(b *testing.B) {
+				benchMixed(b, impl.new(), writes)
+			})
+		}
+	}
+}
+
+// counting is the totalWins["Laseen"]++ case, which sync.Map has no single call for.
+func BenchmarkCount(b *testing.B) {
+	b.Run("shardmap", func(b *testing.B) {
+		m := New[string, int](0)
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				m.Update(benchKeys[i%len(benchKeys)], func(n int, _ bool) int { return n + 1 })
+				i++
+			}
+		})
+	})
+	b.Run("RWMutex", func(b *testing.B) {
+		m := &rwMap{m: map[string]int{}}
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				m.mu.Lock()
+				m.m[benchKeys[i%len(benchKeys)]]++
+				m.mu.Unlock()
+				i++
+			}
+		})
+	})
+}
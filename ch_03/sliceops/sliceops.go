@@ -0,0 +1,178 @@
+// Package sliceops has the slice loops people keep writing by hand.
+//
+// slicesInGo() covers append, copy, clear and slicing, and shows how easily a result shares memory
+// with its input: s6Y := s6X[:2] writes through to s6X, and append may or may not. every function
+// here returns freshly allocated slices with no spare capacity, so changing (or appending to) a
+// result never touches the input. Window's pieces do overlap each other, and it says so; Chunk's
+// don't.
+package sliceops
+
+import "slices"
+
+// Chunk splits s into pieces of n elements; the last one may be shorter. the pieces are cut from
+// one copy of s, each capped at its own length, so they don't alias s and appending to one can't
+// overwrite the next. it panics if n < 1.
+func Chunk[S ~[]E, E any](s S, n int) []S {
+	if n < 1 {
+		panic("sliceops: Chunk size must be at least 1")
+	}
+	cp := slices.Clone(s)
+	out := make([]S, 0, (len(s)+n-1)/n)
+	for i := 0; i < len(cp); i += n {
+		end := min(i+n, len(cp))
+		out = append(out, cp[i:end:end])
+	}
+	return out
+}
+
+// Window returns every run of n consecutive elements: Window([]int{1, 2, 3, 4}, 2) is
+// [[1 2] [2 3] [3 4]]. the windows are cut from one copy of s, so they don't alias s but do overlap
+// each other: writing to an element of one window shows up in its neighbours. appending to a
+// window is safe. there are no windows when n > len(s); it panics if n < 1.
+func Window[S ~[]E, E any](s S, n int) []S {
+	if n < 1 {
+		panic("sliceops: Window size must be at least 1")
+	}
+	if n > len(s) {
+		return nil
+	}
+	cp := slices.Clone(s)
+	out := make([]S, 0, len(s)-n+1)
+	for i := 0; i+n <= len(cp); i++ {
+		out = append(out, cp[i:i+n:i+n])
+	}
+	return out
+}
+
+// Partition splits s into the elements that satisfy keep and those that don't, both in their
+// original order.
+func Partition[S ~[]E, E any](s S, keep func(E) bool) (yes, no S) {
+	n := 0
+	for _, v := range s {
+		if keep(v) {
+			n++
+		}
+	}
+	yes, no = make(S, 0, n), make(S, 0, len(s)-n)
+	for _, v := range s {
+		if keep(v) {
+			yes = append(yes, v)
+		} else {
+			no = append(no, v)
+		}
+	}
+	return yes, no
+}
+
+// GroupBy puts the elements of s in groups by key, each group in the original order. key is called
+// once per element.
+func GroupBy[S ~[]E, E any, K comparable](s S, key func(E) K) map[K]S {
+	// the groups are counted first so each can be made at its final size, as Partition does.
+	keys := make([]K, len(s))
+	sizes := map[K]int{}
+	for i, v := range s {
+		keys[i] = key(v)
+		sizes[keys[i]]++
+	}
+	out := make(map[K]S, len(sizes))
+	for i, v := range s {
+		g, ok := out[keys[i]]
+		if !ok {
+			g = make(S, 0, sizes[keys[i]])
+		}
+		out[keys[i]] = append(g, v)
+	}
+	return out
+}
+
+// DedupStable drops repeated elements, keeping the first of each, in order. unlike slices.Compact
+// it removes repeats anywhere, not just next to each other, which is what putting vals into intSet
+// in mapsInGo() does minus the lost order.
+func DedupStable[S ~[]E, E comparable](s S) S {
+	return DedupStableFunc(s, func(v E) E { return v })
+}
+
+// DedupStableFunc is DedupStable where two elements are repeats when key says they're equal.
+func DedupStableFunc[S ~[]E, E any, K comparable](s S, key func(E) K) S {
+	seen := make(map[K]struct{}, len(s))
+	out := make(S, 0, len(s))
+	for _, v := range s {
+		k := key(v)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		out = append(out, v)
+	}
+	return slices.Clip(out)
+}
+
+// Flatten joins the slices end to end, like slices.Concat for a slice of slices. Concat can leave
+// spare capacity at the end, rounded up to the allocator's size class; Flatten doesn't.
+func Flatten[S ~[]E, E any](ss []S) S {
+	total := 0
+	for _, s := range ss {
+		total += len(s)
+	}
+	out := make(S, 0, total)
+	for _, s := range ss {
+		out = append(out, s...)
+	}
+	return out
+}
+
+// Interleave takes one element from each slice in turn: Interleave([]int{1, 2, 3}, []int{10, 20})
+// is [1 10 2 20 3]. slices that run out are skipped.
+func Interleave[S ~[]E, E any](ss ...S) S {
+	total, longest := 0, 0
+	for _, s := range ss {
+		total += len(s)
+		longest = max(longest, len(s))
+	}
+	out := make(S, 0, total)
+	for i := range longest {
+		for _, s := range ss {
+			if i < len(s) {
+				out = append(out, s[i])
+			}
+		}
+	}
+	return out
+}
+
+// RotateLeft moves the first k elements to the end: RotateLeft([]int{1, 2, 3, 4}, 1) is
+// [2 3 4 1]. k can be negative or larger than len(s).
+func RotateLeft[S ~[]E, E any](s S, k int) S {
+	out := make(S, len(s))
+	if len(s) == 0 {
+		return out
+	}
+	k = ((k % len(s)) + len(s)) % len(s)
+	n := copy(out, s[k:])
+	copy(out[n:], s[:k])
+	return out
+}
+
+// RotateRight moves the last k elements to the front.
+func RotateRight[S ~[]E, E any](s S, k int) S {
+	return RotateLeft(s, -k)
+}
+
+// InsertAt returns s with vs inserted before index i. unlike slices.Insert, which reuses s's
+// array when there's room, the result is always new. it panics if i is out of range [0, len(s)].
+func InsertAt[S ~[]E, E any](s S, i int, vs ...E) S {
+	_ = s[i:] // the same bounds check, and panic, as slices.Insert
+	out := make(S, 0, len(s)+len(vs))
+	out = append(out, s[:i]...)
+	out = append(out, vs...)
+	return append(out, s[i:]...)
+}
+
+// RemoveAt returns s without the elements in [i, j). unlike slices.Delete, which shifts the
+// elements of s itself, s is left alone. it panics if the range is invalid.
+func RemoveAt[S ~[]E, E any](s S, i, j int) S {
+	_ = s[i:j]
+	out := make(S, 0, len(s)-(j-i))
+	out = append(out, s[:i]...)
+	return append(out, s[j:]...)
+}
@@ -0,0 +1,362 @@
+package sliceops
+
+import (
+	"slices"
+	"testing"
+)
+
+// vals from mapsInGo().
+var vals = []int{5, 10, 2, 5, 8, 7, 3, 9, 1, 2, 10}
+
+// fresh checks that every result owns its array: exactly as big as it is, and sharing nothing with
+// the input, so appending to it or writing through it can't reach anything else.
+func fresh[S ~[]E, E any](t *testing.T, name string, got, in S) {
+	t.Helper()
+	if cap(got) != len(got) {
+		t.Errorf("%s: cap %d, len %d", name, cap(got), len(got))
+	}
+	if len(got) > 0 && len(in) > 0 {
+		g, i := &got[:cap(got)][0], &in[:cap(in)][0]
+		for j := range in[:cap(in)] {
+			if &in[:cap(in)][j] == g {
+				t.Errorf("%s: result starts inside the input", name)
+			}
+		}
+		for j := range got {
+			if &got[j] == i {
+				t.Errorf("%s: result contains the input", name)
+			}
+		}
+	}
+}
+
+func TestChunk(t *testing.T) {
+	for n, want := range map[int][][]int{
+		1:  {{5}, {10}, {2}, {5}, {8}, {7}, {3}, {9}, {1}, {2}, {10}},
+		4:  {{5, 10, 2, 5}, {8, 7, 3, 9}, {1, 2, 10}},
+		11: {vals},
+		20: {vals},
+	} {
+		got := Chunk(vals, n)
+		if !slices.EqualFunc(got, want, slices.Equal) {
+			t.Errorf("Chunk(vals, %d) = %v, want %v", n, got, want)
+		}
+		for _, c := range got {
+			fresh(t, "Chunk", c, vals)
+		}
+	}
+	if got := Chunk([]int{}, 3); len(got) != 0 {
+		t.Errorf("Chunk of nothing = %v", got)
+	}
+
+	// appending to one chunk mustn't overwrite the next.
+	cs := Chunk(vals, 4)
+	_ = append(cs[0], -1)
+	if cs[1][0] != 8 {
+		t.Errorf("appending to a chunk changed the next one: %v", cs[1])
+	}
+}
+
+func TestWindow(t *testing.T) {
+	got := Window([]int{1, 2, 3, 4}, 2)
+	if want := [][]int{{1, 2}, {2, 3}, {3, 4}}; !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Window = %v, want %v", got, want)
+	}
+	for _, w := range got {
+		fresh(t, "Window", w, vals)
+	}
+	_ = append(got[0], -1)
+	if got[1][1] != 3 {
+		t.Errorf("appending to a window changed the next one: %v", got[1])
+	}
+	if got := Window(vals, 12); got != nil {
+		t.Errorf("Window longer than the slice = %v", got)
+	}
+	if got := Window(vals, 11); len(got) != 1 || !slices.Equal(got[0], vals) {
+		t.Errorf("Window(vals, len(vals)) = %v", got)
+	}
+}
+
+func TestPanics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"Chunk 0":        func() { Chunk(vals, 0) },
+		"Window 0":       func() { Window(vals, 0) },
+		"InsertAt -1":    func() { InsertAt(vals, -1, 0) },
+		"InsertAt len+1": func() { InsertAt(vals, len(vals)+1, 0) },
+		"RemoveAt j < i": func() { RemoveAt(vals, 3, 2) },
+		"RemoveAt j > n": func() { RemoveAt(vals, 0, len(vals)+1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s didn't panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestPartition(t *testing.T) {
+	even, odd := Partition(vals, func(v int) bool { return v%2 == 0 })
+	if !slices.Equal(even, []int{10, 2, 8, 2, 10}) || !slices.Equal(odd, []int{5, 5, 7, 3, 9, 1}) {
+		t.Errorf("Partition = %v, %v", even, odd)
+	}
+	fresh(t, "Partition yes", even, vals)
+	fresh(t, "Partition no", odd, vals)
+}
+
+func TestGroupBy(t *testing.T) {
+	got := GroupBy(vals, func(v int) int { return v % 3 })
+	want := map[int][]int{0: {3, 9}, 1: {10, 7, 1, 10}, 2: {5, 2, 5, 8, 2}}
+	if len(got) != len(want) {
+		t.Errorf("GroupBy = %v, want %v", got, want)
+	}
+	for k, g := range got {
+		if !slices.Equal(g, want[k]) {
+			t.Errorf("group %d = %v, want %v", k, g, want[k])
+		}
+		fresh(t, "GroupBy", g, vals)
+	}
+
+	calls := 0
+	GroupBy(vals, func(v int) bool { calls++; return v > 5 })
+	if calls != len(vals) {
+		t.Errorf("key called %d times, want %d", calls, len(vals))
+	}
+	if got := GroupBy([]int(nil), func(v int) int { return v }); len(got) != 0 {
+		t.Errorf("GroupBy of nothing = %v", got)
+	}
+}
+
+func TestDedupStable(t *testing.T) {
+	got := DedupStable(vals)
+	if want := []int{5, 10, 2, 8, 7, 3, 9, 1}; !slices.Equal(got, want) {
+		t.Errorf("DedupStable = %v, want %v", got, want)
+	}
+	fresh(t, "DedupStable", got, vals)
+
+	names := []string{"Laseen", "apsalar", "LASEEN", "Apsalar", "kalam"}
+	got2 := DedupStableFunc(names, func(s string) byte { return s[0] | 0x20 })
+	if want := []string{"Laseen", "apsalar", "kalam"}; !slices.Equal(got2, want) {
+		t.Errorf("DedupStableFunc = %v, want %v", got2, want)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	got := Flatten(Chunk(vals, 3))
+	if !slices.Equal(got, vals) {
+		t.Errorf("Flatten(Chunk(vals, 3)) = %v", got)
+	}
+	fresh(t, "Flatten", got, vals)
+	if got := Flatten([][]int{{}, nil, {1}}); !slices.Equal(got, []int{1}) {
+		t.Errorf("Flatten with empty pieces = %v", got)
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	got := Interleave([]int{1, 2, 3}, []int{10, 20}, nil, []int{100})
+	if want := []int{1, 10, 100, 2, 20, 3}; !slices.Equal(got, want) {
+		t.Errorf("Interleave = %v, want %v", got, want)
+	}
+	fresh(t, "Interleave", got, vals)
+	if got := Interleave[[]int](); len(got) != 0 {
+		t.Errorf("Interleave() = %v", got)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	for k, want := range map[int][]int{
+		0:  {1, 2, 3, 4},
+		1:  {2, 3, 4, 1},
+		3:  {4, 1, 2, 3},
+		4:  {1, 2, 3, 4},
+		9:  {2, 3, 4, 1},
+		-1: {4, 1, 2, 3},
+		-6: {3, 4, 1, 2},
+	} {
+		got := RotateLeft(s, k)
+		if !slices.Equal(got, want) {
+			t.Errorf("RotateLeft(%d) = %v, want %v", k, got, want)
+		}
+		if back := RotateRight(got, k); !slices.Equal(back, s) {
+			t.Errorf("RotateRight(RotateLeft(%d)) = %v", k, back)
+		}
+		fresh(t, "RotateLeft", got, s)
+	}
+	if got := RotateLeft([]int{}, 3); len(got) != 0 {
+		t.Errorf("RotateLeft of nothing = %v", got)
+	}
+}
+
+func TestInsertRemove(t *testing.T) {
+	// plenty of spare capacity, which slices.Insert and slices.Delete would use.
+	s := make([]int, 4, 16)
+	copy(s, []int{1, 2, 3, 4})
+
+	got := InsertAt(s, 2, 10, 20)
+	if want := []int{1, 2, 10, 20, 3, 4}; !slices.Equal(got, want) {
+		t.Errorf("InsertAt = %v, want %v", got, want)
+	}
+	fresh(t, "InsertAt", got, s)
+	if got := InsertAt(s, 4, 5); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("InsertAt at the end = %v", got)
+	}
+
+	got = RemoveAt(s, 1, 3)
+	if want := []int{1, 4}; !slices.Equal(got, want) {
+		t.Errorf("RemoveAt = %v, want %v", got, want)
+	}
+	fresh(t, "RemoveAt", got, s)
+	if !slices.Equal(s, []int{1, 2, 3, 4}) || !slices.Equal(s[:6], []int{1, 2, 3, 4, 0, 0}) {
+		t.Errorf("input changed: %v", s[:6])
+	}
+}
+
+// TestAllocs pins down how many allocations each function makes: one per returned slice, plus the
+// copy the pieces of Chunk and Window are cut from. the maps are checked separately.
+func TestAllocs(t *testing.T) {
+	even := func(v int) bool { return v%2 == 0 }
+	chunks := Chunk(vals, 3)
+	for name, c := range map[string]struct {
+		want float64
+		f    func()
+	}{
+		"Chunk":      {2, func() { Chunk(vals, 3) }},
+		"Window":     {2, func() { Window(vals, 3) }},
+		"Partition":  {2, func() { Partition(vals, even) }},
+		"Flatten":    {1, func() { Flatten(chunks) }},
+		"Interleave": {1, func() { Interleave(vals, vals) }},
+		"RotateLeft": {1, func() { RotateLeft(vals, 3) }},
+		"InsertAt":   {1, func() { InsertAt(vals, 3, 1, 2) }},
+		"RemoveAt":   {1, func() { RemoveAt(vals, 3, 5) }},
+	} {
+		if got := testing.AllocsPerRun(100, c.f); got != c.want {
+			t.Errorf("%s: %v allocations, want %v", name, got, c.want)
+		}
+	}
+}
+
+var sink any
+
+// the functions that build maps can't pin a number, since how many allocations a map takes (or
+// whether it stays on the stack) is up to the compiler and runtime. what they can pin is that the
+// slices around the map are allocated once each, however long the input is.
+func TestAllocsWithMaps(t *testing.T) {
+	long := slices.Repeat(vals, 100)
+	mod3 := func(v int) int { return v % 3 }
+
+	// GroupBy: the same three groups from a hundred times the input take the same allocations.
+	// growing the groups by append, as it used to, takes more the longer they get.
+	short := testing.AllocsPerRun(100, func() { GroupBy(vals, mod3) })
+	if got := testing.AllocsPerRun(100, func() { GroupBy(long, mod3) }); got != short {
+		t.Errorf("GroupBy: %v allocations for %d elements, %v for %d", short, len(vals), got, len(long))
+	}
+	appended := testing.AllocsPerRun(100, func() {
+		out := map[int][]int{}
+		for _, v := range long {
+			out[v%3] = append(out[v%3], v)
+		}
+		sink = out
+	})
+	if short >= appended {
+		t.Errorf("GroupBy: %v allocations, appending takes %v", short, appended)
+	}
+
+	// DedupStable: no more than the map of seen keys and the result.
+	for _, s := range [][]int{vals, long} {
+		m := testing.AllocsPerRun(100, func() { sink = make(map[int]struct{}, len(s)) })
+		if got := testing.AllocsPerRun(100, func() { DedupStable(s) }); got > m+1 {
+			t.Errorf("DedupStable of %d elements: %v allocations, want at most %v", len(s), got, m+1)
+		}
+	}
+}
+
+func BenchmarkChunk(b *testing.B) {
+	s := make([]int, 10_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		Chunk(s, 100)
+	}
+}
+
+func BenchmarkWindow(b *testing.B) {
+	s := make([]int, 10_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		Window(s, 100)
+	}
+}
+
+func BenchmarkPartition(b *testing.B) {
+	s := make([]int, 10_000)
+	for i := range s {
+		s[i] = i
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		Partition(s, func(v int) bool { return v%3 == 0 })
+	}
+}
+
+func BenchmarkGroupBy(b *testing.B) {
+	s := make([]int, 10_000)
+	for i := range s {
+		s[i] = i
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		GroupBy(s, func(v int) int { return v % 16 })
+	}
+}
+
+// BenchmarkGroupByAppend is GroupBy without the counting pass, for comparison.
+func BenchmarkGroupByAppend(b *testing.B) {
+	s := make([]int, 10_000)
+	for i := range s {
+		s[i] = i
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		out := map[int][]int{}
+		for _, v := range s {
+			out[v%16] = append(out[v%16], v)
+		}
+	}
+}
+
+func BenchmarkDedupStable(b *testing.B) {
+	s := make([]int, 10_000)
+	for i := range s {
+		s[i] = i % 1000
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		DedupStable(s)
+	}
+}
+
+func BenchmarkInterleave(b *testing.B) {
+	x, y := make([]int, 10_000), make([]int, 5_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		Interleave(x, y)
+	}
+}
+
+func BenchmarkRotateLeft(b *testing.B) {
+	s := make([]int, 10_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		RotateLeft(s, 3_333)
+	}
+}
+
+func BenchmarkInsertAt(b *testing.B) {
+	s := make([]int, 10_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		InsertAt(s, 5_000, 1, 2, 3)
+	}
+}
@@ -0,0 +1,77 @@
+package structlayout
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer reports every struct type, named or anonymous, that reordering would shrink by more
+// than -threshold bytes. sizes are the build's own unless -goarch picks another architecture.
+// structs with fields sized by a type parameter are skipped: how they're padded depends on what
+// they're instantiated with.
+var Analyzer = &analysis.Analyzer{
+	Name:     "structlayout",
+	Doc:      "report structs whose field order wastes memory on padding",
+	URL:      "https://github.com/chiagxziem/learning-go/tree/main/ch_03/structlayout",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var (
+	goarch    string
+	threshold int64
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&goarch, "goarch", "", "lay structs out for this GOARCH instead of the build's")
+	Analyzer.Flags.Int64Var(&threshold, "threshold", 0, "only report structs that would shrink by more than this many bytes")
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	sizes := pass.TypesSizes
+	if goarch != "" {
+		s, err := Sizes(goarch)
+		if err != nil {
+			return nil, err
+		}
+		sizes = s
+	}
+
+	in := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	in.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		st, ok := pass.TypesInfo.Types[n.(*ast.StructType)].Type.(*types.Struct)
+		if !ok || Generic(st) {
+			return
+		}
+		current, best, order := compare(st, sizes)
+		if saved := current.Size - best.Size; saved > max(threshold, 0) {
+			pass.Reportf(n.Pos(), "struct is %d bytes, %d with fields in the order %s (saves %d)",
+				current.Size, best.Size, strings.Join(order, ", "), saved)
+		}
+	})
+	return nil, nil
+}
+
+// Describe is the analyzer's finding for st, for printing next to a Layout.
+func Describe(st *types.Struct, sizes types.Sizes) string {
+	current, best, order := compare(st, sizes)
+	if best.Size == current.Size {
+		return "already optimal"
+	}
+	return fmt.Sprintf("%d bytes in the order %s (saves %d)", best.Size, strings.Join(order, ", "), current.Size-best.Size)
+}
+
+func compare(st *types.Struct, sizes types.Sizes) (current, best Layout, names []string) {
+	order, best := Optimal(st, sizes)
+	names = make([]string, len(order))
+	for i, j := range order {
+		names[i] = st.Field(j).Name()
+	}
+	return Of(st, sizes), best, names
+}
@@ -0,0 +1,31 @@
+package structlayout
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	Analyzer.Flags.Set("goarch", "amd64")
+	defer Analyzer.Flags.Set("goarch", "")
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "padding")
+}
+
+func TestThreshold(t *testing.T) {
+	Analyzer.Flags.Set("goarch", "amd64")
+	Analyzer.Flags.Set("threshold", "8")
+	defer Analyzer.Flags.Set("goarch", "")
+	defer Analyzer.Flags.Set("threshold", "0")
+	results := analysistest.Run(&quietT{T: t}, analysistest.TestData(), Analyzer, "padding")
+	for _, r := range results {
+		for _, d := range r.Diagnostics {
+			t.Errorf("reported with -threshold=8: %s", d.Message)
+		}
+	}
+}
+
+// quietT swallows analysistest's complaints about want comments that aren't met.
+type quietT struct{ *testing.T }
+
+func (*quietT) Errorf(string, ...any) {}
@@ -0,0 +1,160 @@
+// Package structlayout shows what a struct costs in memory.
+//
+// structs() builds person{name, age, pet}, exerciseNo3() builds Employee{firstName, lastName, id}
+// and there's the anonymous city struct, but the chapter never says how big any of them are. the
+// compiler lines every field up on its alignment, so field order decides how much padding goes
+// in between: a bool, an int64 and a bool take 24 bytes on amd64, the same fields with the bools
+// together take 16.
+//
+// Of works out the layout of a *types.Struct for any GOARCH, Optimal finds the order with the
+// least padding, and Analyzer flags structs that waste more than a threshold.
+package structlayout
+
+import (
+	"cmp"
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// Field is one field of a struct and where it lands.
+type Field struct {
+	Name    string
+	Type    string
+	Offset  int64
+	Size    int64
+	Align   int64
+	Padding int64 // bytes of padding after this field, before the next one or the end
+}
+
+// Layout is a whole struct.
+type Layout struct {
+	Size    int64
+	Align   int64
+	Fields  []Field
+	Padding int64 // total bytes of padding, including at the end
+}
+
+// Sizes returns the gc compiler's sizes for goarch ("amd64", "386", "arm64", ...).
+func Sizes(goarch string) (types.Sizes, error) {
+	s := types.SizesFor("gc", goarch)
+	if s == nil {
+		return nil, fmt.Errorf("structlayout: unknown GOARCH %q", goarch)
+	}
+	return s, nil
+}
+
+// Generic reports whether st has a field whose size depends on a type parameter, like the t in
+// struct{ t T; ok bool } inside a generic type. such a struct has no layout until it's
+// instantiated, and Of and Optimal panic on it.
+func Generic(st *types.Struct) bool {
+	for i := range st.NumFields() {
+		if sizedByTypeParam(st.Field(i).Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+// sizedByTypeParam looks through everything that's stored inline. pointers, slices, maps and the
+// rest are the same size whatever they point to.
+func sizedByTypeParam(t types.Type) bool {
+	switch t := types.Unalias(t).(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Array:
+		return sizedByTypeParam(t.Elem())
+	case *types.Struct:
+		return Generic(t)
+	case *types.Named:
+		for targ := range t.TypeArgs().Types() {
+			if sizedByTypeParam(targ) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Of lays out st, which mustn't be Generic.
+func Of(st *types.Struct, sizes types.Sizes) Layout {
+	vars := make([]*types.Var, st.NumFields())
+	for i := range vars {
+		vars[i] = st.Field(i)
+	}
+	return layout(vars, sizes)
+}
+
+func layout(vars []*types.Var, sizes types.Sizes) Layout {
+	offsets := sizes.Offsetsof(vars)
+	l := Layout{Align: 1, Fields: make([]Field, len(vars))}
+	for i, v := range vars {
+		f := Field{
+			Name:   v.Name(),
+			Type:   types.TypeString(v.Type(), (*types.Package).Name),
+			Offset: offsets[i],
+			Size:   sizes.Sizeof(v.Type()),
+			Align:  sizes.Alignof(v.Type()),
+		}
+		l.Align = max(l.Align, f.Align)
+		l.Fields[i] = f
+	}
+
+	// the struct's size comes from a struct built out of the same fields, so it includes the
+	// padding the compiler adds at the end (and the byte it adds after a trailing zero-size field).
+	l.Size = sizes.Sizeof(types.NewStruct(vars, nil))
+	for i := range l.Fields {
+		end := l.Size
+		if i+1 < len(l.Fields) {
+			end = l.Fields[i+1].Offset
+		}
+		l.Fields[i].Padding = end - l.Fields[i].Offset - l.Fields[i].Size
+		l.Padding += l.Fields[i].Padding
+	}
+	if len(l.Fields) == 0 {
+		l.Padding = l.Size
+	}
+	return l
+}
+
+// Optimal returns the field order with the least padding, as indexes into st's fields, and the
+// layout it gives. fields are sorted by alignment, largest first, which is optimal because every
+// size is a multiple of its alignment. zero-size fields go first, since one at the end costs a
+// byte of padding. like Of, it needs a struct that isn't Generic.
+func Optimal(st *types.Struct, sizes types.Sizes) ([]int, Layout) {
+	order := make([]int, st.NumFields())
+	for i := range order {
+		order[i] = i
+	}
+	size := func(i int) int64 { return sizes.Sizeof(st.Field(i).Type()) }
+	align := func(i int) int64 { return sizes.Alignof(st.Field(i).Type()) }
+	slices.SortStableFunc(order, func(a, b int) int {
+		if za, zb := size(a) == 0, size(b) == 0; za != zb {
+			if za {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(align(b), align(a))
+	})
+
+	vars := make([]*types.Var, len(order))
+	for i, j := range order {
+		vars[i] = st.Field(j)
+	}
+	return order, layout(vars, sizes)
+}
+
+// String draws the layout as a table, with a line for every run of padding.
+func (l Layout) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "size %d, align %d, %d bytes of padding\n", l.Size, l.Align, l.Padding)
+	for _, f := range l.Fields {
+		fmt.Fprintf(&b, "  %4d  %-12s %-20s size %d, align %d\n", f.Offset, f.Name, f.Type, f.Size, f.Align)
+		if f.Padding > 0 {
+			fmt.Fprintf(&b, "  %4d  %-12s %d bytes\n", f.Offset+f.Size, "(padding)", f.Padding)
+		}
+	}
+	return b.String()
+}
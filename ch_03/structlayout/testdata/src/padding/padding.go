@@ -0,0 +1,57 @@
+package padding
+
+type wasteful struct { // want `struct is 24 bytes, 16 with fields in the order b, a, c \(saves 8\)`
+	a bool
+	b int64
+	c bool
+}
+
+type tight struct {
+	b int64
+	a bool
+	c bool
+}
+
+// the layout of a generic struct depends on what it's instantiated with, so it's left alone.
+type entry[K comparable, V any] struct {
+	deleted bool
+	key     K
+	val     V
+	seq     int64
+}
+
+type pair[T any] struct {
+	ok   bool
+	vals [2]T
+	n    int64
+}
+
+type wrapper[T any] struct {
+	flag bool
+	e    entry[string, T]
+	n    int64
+}
+
+// a type parameter behind a pointer or in a slice doesn't change the size.
+type node[T any] struct { // want `struct is 48 bytes, 40 with fields in the order next, items, a, b \(saves 8\)`
+	a     bool
+	next  *node[T]
+	b     bool
+	items []T
+}
+
+func generic[T any]() int {
+	var s struct {
+		a bool
+		t T
+		b bool
+	}
+	_ = s
+	return 0
+}
+
+var person = struct { // want `struct is 24 bytes, 16 with fields in the order age, adult, pet`
+	adult bool
+	age   int
+	pet   bool
+}{}